@@ -1,6 +1,9 @@
 package configure
 
 import (
+	"fmt"
+	"github.com/farseer-go/fs/parse"
+	"reflect"
 	"strings"
 )
 
@@ -36,15 +39,29 @@ func (c *config) SetEnvKeyReplacer(r *strings.Replacer) {
 	c.envKeyReplacer = r
 }
 
-// Build 找到并读取配置文件
+// GetSources 配置来源（按优先级）
+func (c *config) GetSources() []string {
+	var sources []string
+	for _, provider := range c.configProvider {
+		if stringer, ok := provider.(fmt.Stringer); ok {
+			sources = append(sources, stringer.String())
+		} else {
+			sources = append(sources, reflect.TypeOf(provider).String())
+		}
+	}
+	return sources
+}
+
+// Build 找到并读取配置文件，某个配置读取失败时，继续读取其它配置，并返回第一个错误
 func (c *config) Build() error {
+	var firstErr error
 	for _, provider := range c.configProvider {
 		err := provider.LoadConfigure()
-		if err != nil {
-			return err
+		if err != nil && firstErr == nil {
+			firstErr = err
 		}
 	}
-	return nil
+	return firstErr
 }
 
 // GetString 读取配置
@@ -91,7 +108,7 @@ func (c *config) GetSlice(key string) []string {
 			if isOk {
 				var arr []string
 				for _, s := range m {
-					arr = append(arr, s.(string))
+					arr = append(arr, parse.Convert(s, ""))
 				}
 				return arr
 			}
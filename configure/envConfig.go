@@ -12,6 +12,11 @@ func NewEnvConfig() *envConfig {
 	return &envConfig{}
 }
 
+// String 配置来源
+func (r *envConfig) String() string {
+	return "环境变量"
+}
+
 func (r *envConfig) LoadConfigure() error {
 	return nil
 }
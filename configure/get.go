@@ -1,13 +1,24 @@
 package configure
 
 import (
+	"fmt"
 	"github.com/farseer-go/fs/parse"
+	"strconv"
 	"strings"
+	"time"
 )
 
+// ReadInConfig 读取配置文件（./farseer.yaml）及环境变量
 func ReadInConfig() error {
+	return ReadInConfigFile("./farseer.yaml")
+}
+
+// ReadInConfigFile 读取配置文件及环境变量，多个配置文件时，后面的文件优先级更高（环境变量的优先级最高）
+func ReadInConfigFile(configFiles ...string) error {
 	configurationBuilder = NewConfigurationBuilder()
-	configurationBuilder.AddYamlFile("./farseer.yaml")
+	for index := len(configFiles) - 1; index >= 0; index-- {
+		configurationBuilder.AddYamlFile(configFiles[index])
+	}
 	configurationBuilder.AddEnvironmentVariables()
 	// 配置文件，我们都是通过a.b访问的。而环境变量是A_B。
 	// 让环境变量支持A.B的方式，使用替换的方式以支持。
@@ -16,6 +27,11 @@ func ReadInConfig() error {
 	return configurationBuilder.Build()
 }
 
+// GetSources 配置来源（按优先级）
+func GetSources() []string {
+	return configurationBuilder.GetSources()
+}
+
 // GetString 获取配置
 func GetString(key string) string {
 	return configurationBuilder.GetString(key)
@@ -41,6 +57,28 @@ func GetBool(key string) bool {
 	return parse.Convert(configurationBuilder.GetString(key), false)
 }
 
+// GetDuration 获取配置，支持：5s、100ms，纯数字时单位为毫秒，未配置或格式不正确时返回0
+func GetDuration(key string) time.Duration {
+	duration, _ := ParseDuration(configurationBuilder.GetString(key))
+	return duration
+}
+
+// ParseDuration 转换成time.Duration，支持：5s、100ms，纯数字时单位为毫秒，空字符串时返回0，格式不正确时返回error
+func ParseDuration(str string) (time.Duration, error) {
+	str = strings.TrimSpace(str)
+	if str == "" {
+		return 0, nil
+	}
+	if ms, err := strconv.ParseInt(str, 10, 64); err == nil {
+		return time.Duration(ms) * time.Millisecond, nil
+	}
+	duration, err := time.ParseDuration(str)
+	if err != nil {
+		return 0, fmt.Errorf("%s无法转换成time.Duration", str)
+	}
+	return duration, nil
+}
+
 // GetSubNodes 获取所有子节点
 func GetSubNodes(key string) map[string]any {
 	return configurationBuilder.GetSubNodes(key)
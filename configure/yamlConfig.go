@@ -19,6 +19,11 @@ func NewYamlConfig(configFile string) *yamlConfig {
 	}
 }
 
+// String 配置来源
+func (r *yamlConfig) String() string {
+	return "yaml：" + r.configFile
+}
+
 func (r *yamlConfig) LoadConfigure() error {
 	data, err := os.ReadFile(r.configFile)
 	if err != nil {
@@ -0,0 +1,79 @@
+package container
+
+import (
+	"fmt"
+	"github.com/farseer-go/fs/container/eumLifecycle"
+	"github.com/farseer-go/fs/exception"
+	"reflect"
+)
+
+// RegisterStruct 注册结构体（或结构体指针），由容器创建并注入字段（interface、[]interface、config标签等）
+// 同时绑定到它实现的接口上，通过接口或结构体Resolve时，按lifecycle共用同一个实例
+// interfaces = 绑定的接口，如：(*IUserRepository)(nil)
+func RegisterStruct[TStruct any](lifecycle eumLifecycle.Enum, interfaces ...any) {
+	if defContainer == nil {
+		exception.ThrowRefuseException("请先调用fs.Initialize[Module]()初始化模块")
+	}
+	var t *TStruct
+	structType := reflect.TypeOf(t).Elem()
+	if !isStruct(structType) {
+		panic(fmt.Sprintf("container：RegisterStruct的泛型只能为结构体（指针），当前为%s", structType.String()))
+	}
+
+	interfaceTypes := getBindTypes(structType, interfaces)
+	model := &componentModel{
+		lifecycle:     lifecycle,
+		interfaceType: structType,
+		instanceType:  structType,
+	}
+	defContainer.register(model)
+	defContainer.bind(model, interfaceTypes)
+}
+
+// Bind 将已注册的结构体（或结构体指针），绑定到它实现的接口上
+// 结构体可通过RegisterStruct注册，或注册出参为结构体（指针）的构造函数
+// interfaces = 绑定的接口，如：(*IUserRepository)(nil)
+func Bind[TStruct any](interfaces ...any) {
+	if defContainer == nil {
+		exception.ThrowRefuseException("请先调用fs.Initialize[Module]()初始化模块")
+	}
+	var t *TStruct
+	structType := reflect.TypeOf(t).Elem()
+	model := findDefaultOrFirstComponent(defContainer.getComponents(structType))
+	if model == nil {
+		panic(fmt.Sprintf("container：Bind时，%s未注册", structType.String()))
+	}
+	defContainer.bind(model, getBindTypes(structType, interfaces))
+}
+
+// 校验结构体是否实现了要绑定的接口
+func getBindTypes(structType reflect.Type, interfaces []any) []reflect.Type {
+	var lst []reflect.Type
+	for _, iface := range interfaces {
+		interfaceType := reflect.TypeOf(iface)
+		if interfaceType != nil && interfaceType.Kind() == reflect.Pointer {
+			interfaceType = interfaceType.Elem()
+		}
+		if interfaceType == nil || interfaceType.Kind() != reflect.Interface {
+			panic(fmt.Sprintf("container：绑定%s时，接口参数只能为(*IFoo)(nil)的形式", structType.String()))
+		}
+		if !structType.Implements(interfaceType) {
+			panic(fmt.Sprintf("container：%s未实现接口%s", structType.String(), interfaceType.String()))
+		}
+		lst = append(lst, interfaceType)
+	}
+	return lst
+}
+
+// 绑定到接口，实例由结构体的注册创建
+func (r *container) bind(target *componentModel, interfaceTypes []reflect.Type) {
+	for _, interfaceType := range interfaceTypes {
+		r.register(&componentModel{
+			name:          target.name,
+			lifecycle:     target.lifecycle,
+			interfaceType: interfaceType,
+			instanceType:  target.instanceType,
+			target:        target,
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package container
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+type ICircularA interface{ A() }
+type ICircularB interface{ B() }
+type ICircularC interface{ C() }
+
+type circularA struct{}
+type circularB struct{}
+type circularC struct{}
+
+func (receiver *circularA) A() {}
+func (receiver *circularB) B() {}
+func (receiver *circularC) C() {}
+
+func TestRegisterCircular(t *testing.T) {
+	InitContainer()
+	Register(func(b ICircularB) ICircularA { return &circularA{} })
+	Register(func(c ICircularC) ICircularB { return &circularB{} })
+
+	defer func() {
+		expected := "container：注册container.ICircularC时，发现循环依赖：container.ICircularC -> container.ICircularA -> container.ICircularB -> container.ICircularC"
+		if exp := recover(); exp != expected {
+			t.Fatalf("expected %s, got: %v", expected, exp)
+		}
+		// 形成循环的注册已撤销
+		if _, err := TryResolve[ICircularC](); !errors.Is(err, ErrNotRegistered) {
+			t.Fatalf("expected ErrNotRegistered, got: %v", err)
+		}
+	}()
+	Register(func(a ICircularA) ICircularC { return &circularC{} })
+}
+
+func TestResolveChainString(t *testing.T) {
+	a := &componentModel{interfaceType: typeOf[ICircularA]()}
+	b := &componentModel{interfaceType: typeOf[ICircularB](), name: "b"}
+	chain := resolveChain{}.add(a).add(b).add(a)
+	if chain.String() != "container.ICircularA -> container.ICircularB(b) -> container.ICircularA" {
+		t.Fatalf("unexpected chain: %s", chain.String())
+	}
+	if !chain.contains(b) {
+		t.Fatal("chain should contain b")
+	}
+}
+
+func typeOf[T any]() reflect.Type {
+	var t *T
+	return reflect.TypeOf(t).Elem()
+}
+
+type IInstanceA interface{ A() }
+type IInstanceB interface{ B() }
+
+type instanceA struct{ B IInstanceB }
+type instanceB struct{}
+
+func (receiver *instanceA) A() {}
+func (receiver *instanceB) B() {}
+
+// 注册的实例不会注入字段，字段中的接口不是依赖项
+func TestRegisterInstanceNotCircular(t *testing.T) {
+	InitContainer()
+	Register(func(a IInstanceA) IInstanceB { return &instanceB{} })
+	RegisterInstance[IInstanceA](&instanceA{B: &instanceB{}})
+
+	if _, err := TryResolve[IInstanceB](); err != nil {
+		t.Fatal(err)
+	}
+	if a := Resolve[IInstanceA](); a.(*instanceA).B == nil {
+		t.Fatal("the field of the registered instance should be kept")
+	}
+}
+
+type IWrapper interface{ Get() string }
+
+type wrapper struct{ inner IWrapper }
+type wrapperImpl struct{}
+
+func (receiver *wrapper) Get() string     { return "cached:" + receiver.inner.Get() }
+func (receiver *wrapperImpl) Get() string { return "impl" }
+
+// 先注册带别名的包装，再注册默认实现，注册时不能把入参当成依赖自己
+func TestRegisterNamedWrapperBeforeDefault(t *testing.T) {
+	InitContainer()
+	Register(func(inner IWrapper) IWrapper { return &wrapper{inner: inner} }, "cached")
+	Register(func() IWrapper { return &wrapperImpl{} })
+
+	if get := Resolve[IWrapper]("cached").Get(); get != "cached:impl" {
+		t.Fatalf("unexpected result: %s", get)
+	}
+	if err := Validate(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+}
+
+// 没有默认实现时，由Validate、Resolve检测到循环依赖
+func TestNamedWrapperWithoutDefault(t *testing.T) {
+	InitContainer()
+	Register(func(inner IWrapper) IWrapper { return &wrapper{inner: inner} }, "cached")
+
+	if _, err := TryResolve[IWrapper]("cached"); !errors.Is(err, ErrCircular) {
+		t.Fatalf("expected ErrCircular, got: %v", err)
+	}
+	var validateErr *ValidateError
+	if err := Validate(); !errors.As(err, &validateErr) || !errors.Is(validateErr.Errors[0], ErrCircular) {
+		t.Fatalf("expected Validate to report ErrCircular, got: %v", err)
+	}
+}
@@ -3,6 +3,8 @@ package container
 import (
 	"github.com/farseer-go/fs/container/eumLifecycle"
 	"reflect"
+	"sync"
+	"sync/atomic"
 )
 
 // 实现类模型
@@ -13,10 +15,19 @@ type componentModel struct {
 	instanceType  reflect.Type      // 函数类型
 	instanceValue reflect.Value     // 函数值
 	instance      any               // 实例
+	lock          sync.Mutex        // 创建单例时加锁，保证只创建一次
+	dispose       func(ins any)     // 自定义的释放函数
+	decorated     bool              // 单例是否已装饰
+	override      bool              // 是否为替身（Override），替身会替换掉同接口同别名的注册
+	target        *componentModel   // 绑定的结构体注册（通过RegisterStruct、Bind绑定到接口时）
+	priority      int               // 优先级，数值越大越优先
+	replace       bool              // 是否替换同接口同别名的已有注册
+	buildingBy    atomic.Int64      // 正在创建单例的协程Id（未在创建时为0）
+	buildingChain resolveChain      // 正在创建单例时的解析链路（只由创建单例的协程读写）
 }
 
-func NewComponentModel(name string, lifecycle eumLifecycle.Enum, interfaceType reflect.Type, funcIns any) componentModel {
-	return componentModel{
+func NewComponentModel(name string, lifecycle eumLifecycle.Enum, interfaceType reflect.Type, funcIns any) *componentModel {
+	return &componentModel{
 		name:          name,
 		lifecycle:     lifecycle,
 		interfaceType: interfaceType,
@@ -25,8 +36,8 @@ func NewComponentModel(name string, lifecycle eumLifecycle.Enum, interfaceType r
 	}
 }
 
-func NewComponentModelByInstance(name string, lifecycle eumLifecycle.Enum, interfaceType reflect.Type, instance any) componentModel {
-	return componentModel{
+func NewComponentModelByInstance(name string, lifecycle eumLifecycle.Enum, interfaceType reflect.Type, instance any) *componentModel {
+	return &componentModel{
 		name:          name,
 		lifecycle:     lifecycle,
 		interfaceType: interfaceType,
@@ -35,3 +46,135 @@ func NewComponentModelByInstance(name string, lifecycle eumLifecycle.Enum, inter
 		instance:      instance,
 	}
 }
+
+// 复制实现类模型，已创建的单例不复制（注册的实例还原为注册时的实例，并重新装饰）
+// 只读取注册后不会变化的字段（dispose由容器的锁保护），不需要加锁
+func (receiver *componentModel) clone() *componentModel {
+	model := &componentModel{
+		name:          receiver.name,
+		lifecycle:     receiver.lifecycle,
+		interfaceType: receiver.interfaceType,
+		instanceType:  receiver.instanceType,
+		instanceValue: receiver.instanceValue,
+		dispose:       receiver.dispose,
+		override:      receiver.override,
+		target:        receiver.target,
+		priority:      receiver.priority,
+		replace:       receiver.replace,
+	}
+	if receiver.isInstance() {
+		model.instance = receiver.instanceValue.Interface()
+	}
+	return model
+}
+
+// 是否为注册的实例（不是构造函数、结构体类型的注册），实例直接返回，不会注入字段
+func (receiver *componentModel) isInstance() bool {
+	return receiver.instanceType.Kind() != reflect.Func && receiver.instanceValue.IsValid()
+}
+
+// 标记当前协程正在创建单例，调用方需持有lock
+func (receiver *componentModel) startBuilding(chain resolveChain) {
+	receiver.buildingChain = chain
+	receiver.buildingBy.Store(goroutineId())
+}
+
+// 单例创建完成，调用方需持有lock
+func (receiver *componentModel) endBuilding() {
+	receiver.buildingBy.Store(0)
+	receiver.buildingChain = nil
+}
+
+// 当前协程正在创建该单例时（构造函数中直接调用Resolve又获取到该单例），返回创建时的解析链路
+func (receiver *componentModel) reentrantChain() resolveChain {
+	if id := receiver.buildingBy.Load(); id != 0 && id == goroutineId() {
+		return receiver.buildingChain
+	}
+	return nil
+}
+
+// 打印实现类，如：IA、IA(name)
+func (receiver *componentModel) String() string {
+	if receiver.interfaceType == nil {
+		return receiver.instanceType.String()
+	}
+	if receiver.name != "" {
+		return receiver.interfaceType.String() + "(" + receiver.name + ")"
+	}
+	return receiver.interfaceType.String()
+}
+
+// 依赖项（构造函数的入参、实例中需要注入的字段）
+type dependItem struct {
+	field         string       // 字段名称（构造函数入参时为空）
+	dependType    reflect.Type // 入参或字段的类型
+	interfaceType reflect.Type // 依赖的接口
+	name          string       // 别名（字段通过inject标签指定）
+	isSlice       bool         // []interface，注入所有实现
+	isLazy        bool         // func() interface、Lazy[interface]，调用时才获取（不会形成循环依赖）
+}
+
+// 打印依赖项，如：IB、Field(IB,name)
+func (receiver dependItem) String() string {
+	str := receiver.dependType.String()
+	if receiver.name != "" {
+		str += "," + receiver.name
+	}
+	if receiver.field != "" {
+		return receiver.field + "(" + str + ")"
+	}
+	return str
+}
+
+// 找出依赖项，构造函数只能找到入参，结构体的注册可以找到需要注入的字段
+func (receiver *componentModel) dependItems() []dependItem {
+	// 绑定到接口的结构体，依赖项在结构体的注册中；注册的实例不会注入字段，没有依赖项
+	if receiver.target != nil || receiver.isInstance() {
+		return nil
+	}
+	var lst []dependItem
+	switch receiver.instanceType.Kind() {
+	case reflect.Func:
+		for inIndex := 0; inIndex < receiver.instanceType.NumIn(); inIndex++ {
+			inType := receiver.instanceType.In(inIndex)
+			if elemType, isLazy := lazyElemType(inType); isLazy {
+				lst = append(lst, dependItem{dependType: inType, interfaceType: elemType, isLazy: true})
+			} else if isInterfaceSlice(inType) {
+				lst = append(lst, dependItem{dependType: inType, interfaceType: inType.Elem(), isSlice: true})
+			} else {
+				lst = append(lst, dependItem{dependType: inType, interfaceType: inType})
+			}
+		}
+	case reflect.Struct, reflect.Pointer:
+		instanceType := receiver.instanceType
+		if instanceType.Kind() == reflect.Pointer {
+			instanceType = instanceType.Elem()
+		}
+		if instanceType.Kind() != reflect.Struct {
+			return nil
+		}
+		for i := 0; i < instanceType.NumField(); i++ {
+			field := instanceType.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			if _, exists := field.Tag.Lookup("config"); exists {
+				continue
+			}
+			name, hasInject := field.Tag.Lookup("inject")
+			// 只有带inject标签的func() interface、Lazy[interface]才需要注入
+			if hasInject {
+				if elemType, isLazy := lazyElemType(field.Type); isLazy {
+					lst = append(lst, dependItem{field: field.Name, dependType: field.Type, interfaceType: elemType, name: name, isLazy: true})
+					continue
+				}
+			}
+			if field.Type.Kind() == reflect.Interface {
+				lst = append(lst, dependItem{field: field.Name, dependType: field.Type, interfaceType: field.Type, name: name})
+			} else if isInterfaceSlice(field.Type) {
+				lst = append(lst, dependItem{field: field.Name, dependType: field.Type, interfaceType: field.Type.Elem(), isSlice: true})
+			}
+		}
+	}
+	return lst
+}
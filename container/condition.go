@@ -0,0 +1,109 @@
+package container
+
+import (
+	"github.com/farseer-go/fs/configure"
+	"github.com/farseer-go/fs/exception"
+	"reflect"
+	"sync"
+)
+
+// Condition 注册条件
+type Condition struct {
+	match      func() bool // 是否满足条件
+	afterOther bool        // 需要在其它注册完成后再判断（如：OnMissing）
+}
+
+// 条件注册
+type conditional struct {
+	condition Condition
+	register  func()
+}
+
+var conditionals []conditional
+var conditionApplied bool
+var conditionLock sync.Mutex
+
+// RegisterIf 满足条件时才执行注册，如：
+//
+//	container.RegisterIf(container.OnConfig("Redis.default"), func() { container.Register(newRedisCache) })
+//	container.RegisterIf(container.OnMissing[ICache](), func() { container.Register(newMemoryCache) })
+//
+// 在模块的PreInitialize中调用时，会在所有模块PreInitialize完成后（此时配置已读取）统一判断，其它阶段调用时立即判断
+func RegisterIf(condition Condition, register func()) {
+	if defContainer == nil {
+		exception.ThrowRefuseException("请先调用fs.Initialize[Module]()初始化模块")
+	}
+	conditionLock.Lock()
+	if !conditionApplied {
+		conditionals = append(conditionals, conditional{condition: condition, register: register})
+		conditionLock.Unlock()
+		return
+	}
+	conditionLock.Unlock()
+
+	if condition.match() {
+		register()
+	}
+}
+
+// ApplyConditions 判断所有条件注册，先判断普通条件，再判断需要在其它注册完成后判断的条件（如：OnMissing）
+// 由框架在所有模块PreInitialize完成后调用
+func ApplyConditions() {
+	conditionLock.Lock()
+	lst := conditionals
+	conditionals = nil
+	conditionApplied = true
+	conditionLock.Unlock()
+
+	for _, afterOther := range []bool{false, true} {
+		for _, item := range lst {
+			if item.condition.afterOther == afterOther && item.condition.match() {
+				item.register()
+			}
+		}
+	}
+}
+
+// 重新初始化容器时，清空条件注册
+func resetConditions() {
+	conditionLock.Lock()
+	defer conditionLock.Unlock()
+	conditionals = nil
+	conditionApplied = false
+}
+
+// When 自定义条件
+func When(fn func() bool) Condition {
+	return Condition{match: fn}
+}
+
+// OnConfig 配置项有值时成立，传入value时，需要配置的值与value相同
+func OnConfig(key string, value ...string) Condition {
+	return Condition{match: func() bool {
+		configValue := configure.GetString(key)
+		if len(value) > 0 {
+			return configValue == value[0]
+		}
+		return configValue != "" || len(configure.GetSubNodes(key)) > 0
+	}}
+}
+
+// OnMissingConfig 配置项没有值时成立
+func OnMissingConfig(key string) Condition {
+	return Condition{match: func() bool {
+		return configure.GetString(key) == "" && len(configure.GetSubNodes(key)) == 0
+	}}
+}
+
+// OnMissing 接口（或指定的别名）未注册时成立，会在其它注册完成后再判断
+// iocName = 别名，不传时判断接口是否有任意实现
+func OnMissing[T any](iocName ...string) Condition {
+	return Condition{afterOther: true, match: func() bool {
+		var t *T
+		componentModels := defContainer.getComponents(reflect.TypeOf(t).Elem())
+		if len(iocName) > 0 {
+			return findComponent(componentModels, iocName[0]) == nil
+		}
+		return len(componentModels) == 0
+	}}
+}
@@ -6,191 +6,521 @@ import (
 	"github.com/farseer-go/fs/flog"
 	"os"
 	"reflect"
+	"sort"
+	"sync"
 )
 
 // 容器
 type container struct {
 	name       string
-	dependency map[reflect.Type][]componentModel // 依赖
-	component  []componentModel                  // 实现类
+	parent     *container                         // 父容器（作用域容器才有）
+	lock       *sync.RWMutex                      // 注册信息的读写锁（与父容器共用）
+	dependency map[reflect.Type][]*componentModel // 依赖
+	component  []*componentModel                  // 实现类
+	scopedLock sync.Mutex                         // 作用域实例的锁
+	scoped     map[*componentModel]*scopedIns     // 当前作用域内创建的实例
+	createdIns []createdIns                       // 按创建顺序记录的实例（单例记录在根容器，Scoped记录在所在的作用域），用于释放
+	decorators map[reflect.Type][]decorator       // 装饰器
+	intercepts map[reflect.Type][]Interceptor     // 拦截器
+}
+
+// 作用域内的实例
+type scopedIns struct {
+	lock     sync.Mutex // 创建实例时加锁，保证同一个作用域内只创建一次
+	instance any
 }
 
 // NewContainer 实例化一个默认容器
 func NewContainer() *container {
 	return &container{
 		name:       "default",
-		dependency: make(map[reflect.Type][]componentModel),
-		component:  []componentModel{},
+		lock:       &sync.RWMutex{},
+		dependency: make(map[reflect.Type][]*componentModel),
+		component:  []*componentModel{},
+		scoped:     make(map[*componentModel]*scopedIns),
+		decorators: make(map[reflect.Type][]decorator),
+		intercepts: make(map[reflect.Type][]Interceptor),
 	}
 }
 
-// 注册实例，添加到依赖列表
-func (r *container) addComponent(model componentModel) {
-	componentModels, exists := r.dependency[model.interfaceType]
-	if !exists {
-		r.dependency[model.interfaceType] = []componentModel{model}
-	} else {
-		for index := 0; index < len(componentModels); index++ {
-			if componentModels[index].name == model.name && componentModels[index].instanceType == model.instanceType {
-				panic(fmt.Sprintf("container：已存在同样的注册对象,interfaceType=%s,name=%s,instanceType=%s", model.interfaceType.String(), model.name, reflect.TypeOf(model.instanceType).String()))
-			}
+// 创建子作用域，与父容器共用注册信息，Scoped的实例在子作用域内单独创建
+func (r *container) newScope() *container {
+	return &container{
+		name:       "scope",
+		parent:     r,
+		lock:       r.lock,
+		dependency: r.dependency,
+		scoped:     make(map[*componentModel]*scopedIns),
+		decorators: r.decorators,
+		intercepts: r.intercepts,
+	}
+}
+
+// 根容器
+func (r *container) root() *container {
+	if r.parent == nil {
+		return r
+	}
+	return r.parent.root()
+}
+
+// 注册实例，添加到依赖列表，被替身（Override）替换的注册会被忽略
+// 设置了replace时，会先移除同接口同别名的注册
+func (r *container) addComponent(model *componentModel) bool {
+	for _, item := range r.dependency[model.interfaceType] {
+		if item.name != model.name {
+			continue
+		}
+		if item.override && !model.override {
+			return false
+		}
+		if model.replace {
+			r.removeComponent(item)
+			continue
+		}
+		if item.instanceType == model.instanceType {
+			panic(fmt.Sprintf("container：已存在同样的注册对象,interfaceType=%s,name=%s,instanceType=%s（可使用WithReplace()替换）", model.interfaceType.String(), model.name, model.instanceType.String()))
 		}
-		r.dependency[model.interfaceType] = append(componentModels, model)
 	}
+	r.dependency[model.interfaceType] = append(r.dependency[model.interfaceType], model)
 	r.component = append(r.component, model)
+	return true
+}
+
+// 注册，并检查循环依赖
+func (r *container) register(model *componentModel) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if r.addComponent(model) {
+		r.checkCircular(model)
+	}
 }
 
 // 注册构造函数
 func (r *container) registerConstructor(constructor any, name string, lifecycle eumLifecycle.Enum) {
+	r.register(newConstructorModel(constructor, name, lifecycle))
+}
+
+// 注册实例
+func (r *container) registerInstance(interfaceType any, ins any, name string, lifecycle eumLifecycle.Enum) {
+	r.register(newInstanceModel(interfaceType, ins, name, lifecycle))
+}
+
+// 校验构造函数，并生成实现类模型
+func newConstructorModel(constructor any, name string, lifecycle eumLifecycle.Enum) *componentModel {
 	constructorType := reflect.TypeOf(constructor)
 	for inIndex := 0; inIndex < constructorType.NumIn(); inIndex++ {
 		if name == "" && constructorType.In(inIndex).String() == constructorType.String() {
 			panic("container：构造函数注册，当未设置别名时，入参的类型不能与返回的接口类型一样")
 		}
 
-		if constructorType.In(inIndex).Kind() != reflect.Interface {
-			panic("container：构造函数注册，入参类型必须为interface")
+		if _, isLazy := lazyElemType(constructorType.In(inIndex)); !isLazy && constructorType.In(inIndex).Kind() != reflect.Interface && !isInterfaceSlice(constructorType.In(inIndex)) {
+			panic("container：构造函数注册，入参类型必须为interface、[]interface、func() interface或Lazy[interface]")
 		}
 	}
-	if constructorType.NumOut() != 1 {
-		panic("container：构造函数注册，只能有1个出参")
+	// 出参可以是：IFoo 或 (IFoo, error)
+	if constructorType.NumOut() == 2 {
+		if constructorType.Out(1) != errorType {
+			panic("container：构造函数注册，第2个出参只能为error")
+		}
+	} else if constructorType.NumOut() != 1 {
+		panic("container：构造函数注册，只能有1个出参，或(interface, error)2个出参")
 	}
+	// 出参为结构体（或指针）时，以具体类型注册，可通过Bind绑定到接口
 	interfaceType := constructorType.Out(0)
-	if interfaceType.Kind() != reflect.Interface {
-		panic("container：构造函数注册，出参类型只能为Interface")
+	if interfaceType.Kind() != reflect.Interface && !isStruct(interfaceType) {
+		panic("container：构造函数注册，出参类型只能为Interface或结构体（指针）")
 	}
-	model := NewComponentModel(name, lifecycle, interfaceType, constructor)
-	r.addComponent(model)
+	return NewComponentModel(name, lifecycle, interfaceType, constructor)
 }
 
-// 注册实例
-func (r *container) registerInstance(interfaceType any, ins any, name string, lifecycle eumLifecycle.Enum) {
+// 校验实例，并生成实现类模型
+func newInstanceModel(interfaceType any, ins any, name string, lifecycle eumLifecycle.Enum) *componentModel {
 	interfaceTypeOf := reflect.TypeOf(interfaceType)
 	if interfaceTypeOf.Kind() == reflect.Pointer {
 		interfaceTypeOf = interfaceTypeOf.Elem()
 	}
-	if interfaceTypeOf.Kind() != reflect.Interface {
-		flog.Error("container：实例注册，interfaceType类型只能为Interface")
+	if interfaceTypeOf.Kind() != reflect.Interface && !isStruct(interfaceTypeOf) {
+		flog.Error("container：实例注册，interfaceType类型只能为Interface或结构体（指针）")
+		os.Exit(-1)
+	}
+	if !reflect.TypeOf(ins).AssignableTo(interfaceTypeOf) {
+		flog.Errorf("container：实例注册，%s不能转换成%s", reflect.TypeOf(ins).String(), interfaceTypeOf.String())
 		os.Exit(-1)
 	}
 	model := NewComponentModelByInstance(name, lifecycle, interfaceTypeOf, ins)
 	model.instance = ins
-	r.addComponent(model)
+	return model
+}
+
+// 注册时检查是否形成了循环依赖，存在时撤销注册
+func (r *container) checkCircular(model *componentModel) {
+	if chain := r.findCircular(model, nil, false); chain != nil {
+		r.removeComponent(model)
+		panic(fmt.Sprintf("container：注册%s时，发现循环依赖：%s", model.String(), chain.String()))
+	}
+}
+
+// 从依赖列表中移除
+func (r *container) removeComponent(model *componentModel) {
+	var componentModels []*componentModel
+	for _, item := range r.dependency[model.interfaceType] {
+		if item != model {
+			componentModels = append(componentModels, item)
+		}
+	}
+	if len(componentModels) == 0 {
+		delete(r.dependency, model.interfaceType)
+	} else {
+		r.dependency[model.interfaceType] = componentModels
+	}
+
+	var components []*componentModel
+	for _, item := range r.component {
+		if item != model {
+			components = append(components, item)
+		}
+	}
+	r.component = components
 }
 
 // 获取对象
-func (r *container) resolve(interfaceType reflect.Type, name string) any {
-	if interfaceType.Kind() == reflect.Pointer {
+func (r *container) resolve(interfaceType reflect.Type, name string, chain resolveChain) (any, error) {
+	// 注册过的结构体（或指针）
+	if isStruct(interfaceType) {
+		if model := findComponent(r.getComponents(interfaceType), name); model != nil {
+			return r.getOrCreateIns(model, chain)
+		}
+	}
+
+	if interfaceType.Kind() == reflect.Pointer && interfaceType.Elem().Kind() == reflect.Interface {
 		interfaceType = interfaceType.Elem()
 	}
 
 	// 通过Interface查找注册过的container
 	if interfaceType.Kind() == reflect.Interface {
-		componentModels, exists := r.dependency[interfaceType]
-		if !exists {
-			flog.Errorf("container：%s未注册", interfaceType.String())
-			return nil
+		componentModels := r.getComponents(interfaceType)
+		if len(componentModels) == 0 {
+			return nil, fmt.Errorf("container：%s%w", interfaceType.String(), ErrNotRegistered)
 		}
 
-		for i := 0; i < len(componentModels); i++ {
-			// 找到了实现类
-			if componentModels[i].name == name {
-				return r.getOrCreateIns(interfaceType, i)
-			}
+		// 找到了实现类
+		if model := findComponent(componentModels, name); model != nil {
+			return r.getOrCreateIns(model, chain)
 		}
-		flog.Errorf("container：%s未注册，name=%s", interfaceType.String(), name)
+		return nil, fmt.Errorf("container：%s%w，name=%s", interfaceType.String(), ErrNotRegistered, name)
+	}
 
-		// 结构对象，直接动态创建
-	} else if interfaceType.Kind() == reflect.Struct {
-		return r.createIns(componentModel{
+	// 未注册的结构体（或指针），直接动态创建
+	if isStruct(interfaceType) {
+		return r.createIns(&componentModel{
 			instanceType: interfaceType,
-		})
+		}, chain)
 	}
-	return nil
+	return nil, fmt.Errorf("container：%s%w", interfaceType.String(), ErrNotRegistered)
 }
 
 // 根据lifecycle获取实例
-func (r *container) getOrCreateIns(interfaceType reflect.Type, index int) any {
-	// 单例
-	if r.dependency[interfaceType][index].lifecycle == eumLifecycle.Single {
-		if r.dependency[interfaceType][index].instance == nil {
-			r.dependency[interfaceType][index].instance = r.createIns(r.dependency[interfaceType][index])
+func (r *container) getOrCreateIns(model *componentModel, chain resolveChain) (any, error) {
+	// 当前实现已经在解析链路中，说明存在循环依赖
+	if chain.contains(model) {
+		return nil, fmt.Errorf("container：%w：%s", ErrCircular, chain.add(model).String())
+	}
+	chain = chain.add(model)
+
+	switch model.lifecycle {
+	// 单例，由根容器创建，避免依赖到作用域内的实例
+	case eumLifecycle.Single:
+		// 构造函数中直接调用Resolve，又获取到当前协程正在创建的单例，加锁会导致死锁
+		if buildingChain := model.reentrantChain(); buildingChain != nil {
+			return nil, fmt.Errorf("container：%w：%s", ErrCircular, append(buildingChain[:len(buildingChain):len(buildingChain)], chain...).String())
 		}
-		return r.dependency[interfaceType][index].instance
-	} else {
-		return r.createIns(r.dependency[interfaceType][index])
+		model.lock.Lock()
+		defer model.lock.Unlock()
+		if model.instance == nil {
+			model.startBuilding(chain)
+			defer model.endBuilding()
+			ins, err := r.root().createIns(model, chain)
+			if err != nil {
+				return nil, err
+			}
+			model.instance = ins
+			if model.target == nil {
+				r.root().addCreatedIns(model, ins)
+			}
+		}
+		// 注册的实例、创建的单例，都只装饰一次
+		if !model.decorated {
+			model.instance = r.root().decorate(model, model.instance)
+			model.decorated = true
+		}
+		return model.instance, nil
+	// 作用域，同一个作用域内只创建一次
+	case eumLifecycle.Scoped:
+		// 单例由根容器创建并被整个应用共用，不能依赖作用域内的实例
+		for _, item := range chain[:len(chain)-1] {
+			if item.lifecycle == eumLifecycle.Single {
+				return nil, fmt.Errorf("container：单例%s依赖的%s%w", item.String(), model.String(), ErrScoped)
+			}
+		}
+		// 在根容器中获取时，实例会一直缓存在根容器中
+		if r.parent == nil {
+			return nil, fmt.Errorf("container：%s%w", model.String(), ErrScoped)
+		}
+		scoped := r.getScopedIns(model)
+		scoped.lock.Lock()
+		defer scoped.lock.Unlock()
+		if scoped.instance == nil {
+			ins, err := r.createIns(model, chain)
+			if err != nil {
+				return nil, err
+			}
+			scoped.instance = r.decorate(model, ins)
+			if model.target == nil {
+				r.addCreatedIns(model, ins)
+			}
+		}
+		return scoped.instance, nil
+	default:
+		ins, err := r.createIns(model, chain)
+		if err != nil {
+			return nil, err
+		}
+		return r.decorate(model, ins), nil
+	}
+}
+
+// 获取当前作用域内的实例（不存在时添加一个空的占位）
+func (r *container) getScopedIns(model *componentModel) *scopedIns {
+	r.scopedLock.Lock()
+	defer r.scopedLock.Unlock()
+	scoped, exists := r.scoped[model]
+	if !exists {
+		scoped = &scopedIns{}
+		r.scoped[model] = scoped
 	}
+	return scoped
 }
 
 // 根据类型，动态创建实例
-func (r *container) createIns(model componentModel) any {
+func (r *container) createIns(model *componentModel, chain resolveChain) (any, error) {
+	// 绑定到接口的结构体，由结构体的注册来创建
+	if model.target != nil {
+		return r.getOrCreateIns(model.target, chain)
+	}
+
 	if model.instanceType.Kind() == reflect.Func {
-		var arr []reflect.Value
+		building := newBuildingChain(chain)
+		defer building.done()
+		arr := []reflect.Value{}
 		// 构造函数，需要分别取出入参值
 		for inIndex := 0; inIndex < model.instanceType.NumIn(); inIndex++ {
-			val := reflect.ValueOf(r.resolveDefaultOrFirstComponent(model.instanceType.In(inIndex)))
-			arr = append(arr, val)
+			inType := model.instanceType.In(inIndex)
+			// func() interface、Lazy[interface]，调用时才获取
+			if lazyVal, isLazy := newLazyValue(inType, func(interfaceType reflect.Type) (any, error) {
+				return r.resolveDefaultOrFirstComponent(interfaceType, building.get())
+			}); isLazy {
+				arr = append(arr, lazyVal)
+				continue
+			}
+
+			// []interface，注入所有实现
+			if isInterfaceSlice(inType) {
+				lst, err := r.resolveAll(inType.Elem(), chain)
+				if err != nil {
+					return nil, err
+				}
+				arr = append(arr, lst)
+				continue
+			}
+
+			ins, err := r.resolveDefaultOrFirstComponent(inType, chain)
+			if err != nil {
+				return nil, err
+			}
+			if ins == nil {
+				arr = append(arr, reflect.Zero(inType))
+			} else {
+				arr = append(arr, reflect.ValueOf(ins))
+			}
 		}
-		if arr == nil {
-			arr = []reflect.Value{}
+
+		// 构造函数返回了error
+		outs := model.instanceValue.Call(arr)
+		if len(outs) == 2 && !outs[1].IsNil() {
+			return nil, fmt.Errorf("container：%s构造失败：%w", model.String(), outs[1].Interface().(error))
 		}
-		return r.inject(model.instanceValue.Call(arr)[0].Interface())
+		return r.inject(outs[0].Interface(), chain)
 	}
 
 	if model.instanceType.Kind() == reflect.Struct || model.instanceType.Kind() == reflect.Pointer {
 		if model.instance != nil {
-			return r.inject(model.instance)
+			return r.inject(model.instance, chain)
 		} else {
-			return r.injectByType(model.instanceType)
+			return r.injectByType(model.instanceType, chain)
 		}
 	}
-	return nil
+	return nil, nil
 }
 
 // 获取对象，如果默认别名不存在，则使用第一个注册的实例
-func (r *container) resolveDefaultOrFirstComponent(interfaceType reflect.Type) any {
-	componentModels, exists := r.dependency[interfaceType]
-	if !exists {
-		flog.Errorf("container：%s未注册", interfaceType.String())
-		return nil
+func (r *container) resolveDefaultOrFirstComponent(interfaceType reflect.Type, chain resolveChain) (any, error) {
+	model := findDefaultOrFirstComponent(r.getComponents(interfaceType))
+	if model == nil {
+		return nil, fmt.Errorf("container：%s%w", interfaceType.String(), ErrNotRegistered)
 	}
+	return r.getOrCreateIns(model, chain)
+}
 
-	findIndex := 0
-	// 优先找默认实例
-	for i := 0; i < len(componentModels); i++ {
-		// 找到了实现类
-		if componentModels[i].name == "" {
-			findIndex = i
+// 获取接口的所有实现，按优先级、注册顺序返回[]interface
+func (r *container) resolveAll(interfaceType reflect.Type, chain resolveChain) (reflect.Value, error) {
+	componentModels := sortByPriority(r.getComponents(interfaceType))
+	lst := reflect.MakeSlice(reflect.SliceOf(interfaceType), 0, len(componentModels))
+	for _, model := range componentModels {
+		ins, err := r.getOrCreateIns(model, chain)
+		if err != nil {
+			return lst, err
+		}
+		if ins == nil {
+			lst = reflect.Append(lst, reflect.Zero(interfaceType))
+		} else {
+			lst = reflect.Append(lst, reflect.ValueOf(ins))
 		}
 	}
-	return r.getOrCreateIns(interfaceType, findIndex)
+	return lst, nil
+}
+
+// 加读锁获取接口的实现类（复制一份，避免与注册并发冲突）
+func (r *container) getComponents(interfaceType reflect.Type) []*componentModel {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	componentModels := make([]*componentModel, len(r.dependency[interfaceType]))
+	copy(componentModels, r.dependency[interfaceType])
+	return componentModels
 }
 
 // 解析注入
-func (r *container) inject(ins any) any {
+func (r *container) inject(ins any, chain resolveChain) (any, error) {
 	if ins == nil {
-		return ins
+		return ins, nil
 	}
 	insVal := reflect.Indirect(reflect.ValueOf(ins))
+	if insVal.Kind() != reflect.Struct {
+		return ins, nil
+	}
+	if err := r.injectFields(insVal, chain); err != nil {
+		return nil, err
+	}
+	return ins, nil
+}
+
+// 解析注入
+func (r *container) injectByType(instanceType reflect.Type, chain resolveChain) (any, error) {
+	// 结构体指针
+	if instanceType.Kind() == reflect.Pointer {
+		instanceVal := reflect.New(instanceType.Elem())
+		if err := r.injectFields(instanceVal.Elem(), chain); err != nil {
+			return nil, err
+		}
+		return instanceVal.Interface(), nil
+	}
+
+	instanceVal := reflect.New(instanceType).Elem()
+	if err := r.injectFields(instanceVal, chain); err != nil {
+		return nil, err
+	}
+	return instanceVal.Interface(), nil
+}
+
+// 为结构体的字段注入实例
+func (r *container) injectFields(insVal reflect.Value, chain resolveChain) error {
+	building := newBuildingChain(chain)
+	defer building.done()
 	for i := 0; i < insVal.NumField(); i++ {
 		field := insVal.Type().Field(i)
-		if field.IsExported() && field.Type.Kind() == reflect.Interface {
-			fieldIns := r.resolve(field.Type, field.Tag.Get("inject"))
-			insVal.Field(i).Set(reflect.ValueOf(fieldIns))
+		if !field.IsExported() {
+			continue
+		}
+
+		// 配置注入
+		if configKey, exists := field.Tag.Lookup("config"); exists {
+			if err := injectConfig(insVal.Field(i), configKey); err != nil {
+				return fmt.Errorf("container：%s.%s注入配置失败：%w", insVal.Type().String(), field.Name, err)
+			}
+			continue
+		}
+
+		// 带inject标签的func() interface、Lazy[interface]，调用时才获取
+		if name, exists := field.Tag.Lookup("inject"); exists {
+			if lazyVal, isLazy := newLazyValue(field.Type, func(interfaceType reflect.Type) (any, error) {
+				return r.resolve(interfaceType, name, building.get())
+			}); isLazy {
+				insVal.Field(i).Set(lazyVal)
+				continue
+			}
+		}
+
+		switch {
+		case field.Type.Kind() == reflect.Interface:
+			fieldIns, err := r.resolve(field.Type, field.Tag.Get("inject"), chain)
+			if err != nil {
+				return err
+			}
+			if fieldIns != nil {
+				insVal.Field(i).Set(reflect.ValueOf(fieldIns))
+			}
+		// []interface，注入所有实现
+		case isInterfaceSlice(field.Type) && len(r.getComponents(field.Type.Elem())) > 0:
+			lst, err := r.resolveAll(field.Type.Elem(), chain)
+			if err != nil {
+				return err
+			}
+			insVal.Field(i).Set(lst)
 		}
 	}
-	return ins
+	return nil
 }
 
-// 解析注入
-func (r *container) injectByType(instanceType reflect.Type) any {
-	instanceVal := reflect.New(instanceType).Elem()
-	for i := 0; i < instanceVal.NumField(); i++ {
-		field := instanceVal.Type().Field(i)
-		if field.IsExported() && field.Type.Kind() == reflect.Interface {
-			fieldIns := r.resolve(field.Type, field.Tag.Get("inject"))
-			instanceVal.Field(i).Set(reflect.ValueOf(fieldIns))
+// 根据别名查找实现类，同别名有多个时，取优先级最高的，优先级相同时取最后注册的
+func findComponent(componentModels []*componentModel, name string) *componentModel {
+	var find *componentModel
+	for _, model := range componentModels {
+		if model.name == name && (find == nil || model.priority >= find.priority) {
+			find = model
 		}
 	}
-	return instanceVal.Interface()
+	return find
+}
+
+// 查找实现类，优先找默认别名，不存在时使用优先级最高的（优先级相同时取第一个注册的）
+func findDefaultOrFirstComponent(componentModels []*componentModel) *componentModel {
+	if find := findComponent(componentModels, ""); find != nil {
+		return find
+	}
+
+	var find *componentModel
+	for _, model := range componentModels {
+		if find == nil || model.priority > find.priority {
+			find = model
+		}
+	}
+	return find
+}
+
+// 按优先级从高到低排序，优先级相同时按注册顺序
+func sortByPriority(componentModels []*componentModel) []*componentModel {
+	sort.SliceStable(componentModels, func(i, j int) bool {
+		return componentModels[i].priority > componentModels[j].priority
+	})
+	return componentModels
+}
+
+// 是否为结构体或结构体指针
+func isStruct(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct || (t.Kind() == reflect.Pointer && t.Elem().Kind() == reflect.Struct)
+}
+
+// 是否为[]interface类型
+func isInterfaceSlice(t reflect.Type) bool {
+	return t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Interface
 }
@@ -0,0 +1,65 @@
+package container
+
+import (
+	"github.com/farseer-go/fs/exception"
+	"reflect"
+)
+
+// 装饰器
+type decorator struct {
+	names []string            // 只装饰指定别名的实现，为空时装饰所有实现
+	fn    func(inner any) any // 装饰函数
+}
+
+// 是否需要装饰该实现
+func (receiver decorator) match(model *componentModel) bool {
+	if len(receiver.names) == 0 {
+		return true
+	}
+	for _, name := range receiver.names {
+		if name == model.name {
+			return true
+		}
+	}
+	return false
+}
+
+// Decorate 为已注册的接口添加装饰器，不需要修改实现类的构造函数即可包装一层（如：缓存、指标）
+// 多个装饰器按添加顺序由内向外包装；单例只装饰一次，需在第一次Resolve之前添加
+// iocName = 只装饰指定别名的实现，不传时装饰该接口的所有实现
+func Decorate[T any](fn func(inner T) T, iocName ...string) {
+	if defContainer == nil {
+		exception.ThrowRefuseException("请先调用fs.Initialize[Module]()初始化模块")
+	}
+	var t *T
+	interfaceType := reflect.TypeOf(t).Elem()
+	if interfaceType.Kind() != reflect.Interface {
+		exception.ThrowRefuseExceptionf("container：Decorate的泛型只能为interface，当前为%s", interfaceType.String())
+	}
+
+	defContainer.lock.Lock()
+	defer defContainer.lock.Unlock()
+	defContainer.decorators[interfaceType] = append(defContainer.decorators[interfaceType], decorator{
+		names: iocName,
+		fn: func(inner any) any {
+			return fn(inner.(T))
+		},
+	})
+}
+
+// 使用装饰器包装实例
+func (r *container) decorate(model *componentModel, ins any) any {
+	if ins == nil || model.interfaceType == nil {
+		return ins
+	}
+	r.lock.RLock()
+	decorators := r.decorators[model.interfaceType]
+	r.lock.RUnlock()
+
+	for _, d := range decorators {
+		if d.match(model) {
+			ins = d.fn(ins)
+		}
+	}
+	return ins
+}
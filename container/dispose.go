@@ -0,0 +1,125 @@
+package container
+
+import (
+	"github.com/farseer-go/fs/container/eumLifecycle"
+	"github.com/farseer-go/fs/exception"
+	"github.com/farseer-go/fs/flog"
+	"github.com/farseer-go/fs/stopwatch"
+	"reflect"
+)
+
+// 由容器创建的实例
+type createdIns struct {
+	model    *componentModel
+	instance any
+}
+
+// 实现了Close() error的组件，如：io.Closer
+type closerWithError interface {
+	Close() error
+}
+
+// 实现了Close()的组件
+type closer interface {
+	Close()
+}
+
+// Dispose 应用关闭时，按创建顺序的倒序释放容器创建的单例
+// 组件实现了Close() error、Close()，或通过OnDispose注册了释放函数时才会释放
+func Dispose() {
+	if defContainer == nil {
+		return
+	}
+	flog.Println("Container组件释放...")
+	defContainer.dispose(true)
+	flog.Println("---------------------------------------")
+}
+
+// OnDispose 为已注册的组件设置释放函数，应用关闭或作用域结束时调用
+// iocName = 别名
+func OnDispose[T any](fn func(ins T), iocName ...string) {
+	if defContainer == nil {
+		exception.ThrowRefuseException("请先调用fs.Initialize[Module]()初始化模块")
+	}
+	name := ""
+	if len(iocName) > 0 {
+		name = iocName[0]
+	}
+	var t *T
+	interfaceType := reflect.TypeOf(t).Elem()
+	model := findComponent(defContainer.getComponents(interfaceType), name)
+	if model == nil {
+		exception.ThrowRefuseExceptionf("container：%s未注册，name=%s", interfaceType.String(), name)
+	}
+
+	defContainer.lock.Lock()
+	defer defContainer.lock.Unlock()
+	model.dispose = func(ins any) {
+		fn(ins.(T))
+	}
+}
+
+// 记录创建的实例
+func (r *container) addCreatedIns(model *componentModel, ins any) {
+	r.scopedLock.Lock()
+	defer r.scopedLock.Unlock()
+	r.createdIns = append(r.createdIns, createdIns{model: model, instance: ins})
+}
+
+// 按创建顺序的倒序释放实例，并清空当前作用域
+func (r *container) dispose(printLog bool) {
+	r.scopedLock.Lock()
+	lst := r.createdIns
+	r.createdIns = nil
+	r.scoped = make(map[*componentModel]*scopedIns)
+	r.scopedLock.Unlock()
+
+	sw := stopwatch.StartNew()
+	for i := len(lst) - 1; i >= 0; i-- {
+		model, ins := lst[i].model, lst[i].instance
+		if model.lifecycle == eumLifecycle.Single {
+			model.lock.Lock()
+			model.instance = nil
+			model.decorated = false
+			model.lock.Unlock()
+		}
+
+		r.lock.RLock()
+		disposeFn := model.dispose
+		r.lock.RUnlock()
+
+		sw.Restart()
+		if !disposeIns(model, ins, disposeFn) {
+			continue
+		}
+		if printLog {
+			flog.Println("耗时：" + sw.GetMillisecondsText() + model.String() + ".Close()")
+		}
+	}
+}
+
+// 释放实例，返回是否有执行释放
+func disposeIns(model *componentModel, ins any, disposeFn func(ins any)) bool {
+	var fn func()
+	switch c := ins.(type) {
+	case closerWithError:
+		fn = func() {
+			if err := c.Close(); err != nil {
+				flog.Errorf("container：%s释放失败：%s", model.String(), err.Error())
+			}
+		}
+	case closer:
+		fn = c.Close
+	}
+	if disposeFn != nil {
+		fn = func() { disposeFn(ins) }
+	}
+	if fn == nil {
+		return false
+	}
+
+	exception.Try(fn).CatchException(func(exp any) {
+		flog.Errorf("container：%s释放失败：%v", model.String(), exp)
+	})
+	return true
+}
@@ -0,0 +1,18 @@
+package container
+
+import (
+	"errors"
+	"reflect"
+)
+
+var (
+	// ErrNotRegistered 未注册
+	ErrNotRegistered = errors.New("未注册")
+	// ErrCircular 存在循环依赖
+	ErrCircular = errors.New("存在循环依赖")
+	// ErrScoped Scoped组件只能在作用域中获取（单例也不能依赖Scoped组件）
+	ErrScoped = errors.New("是Scoped组件，只能在NewScope()创建的作用域中获取")
+)
+
+// 构造函数第2个出参的类型
+var errorType = reflect.TypeOf((*error)(nil)).Elem()
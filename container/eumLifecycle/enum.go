@@ -5,4 +5,17 @@ type Enum int
 const (
 	Transient Enum = iota // 临时
 	Single                // 单例
+	Scoped                // 作用域（同一个作用域内只创建一次）
 )
+
+func (r Enum) ToString() string {
+	switch r {
+	case Transient:
+		return "Transient"
+	case Single:
+		return "Single"
+	case Scoped:
+		return "Scoped"
+	}
+	return "Transient"
+}
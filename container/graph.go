@@ -0,0 +1,88 @@
+package container
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ComponentGraph 组件的依赖关系
+type ComponentGraph struct {
+	Id        string   `json:"id"`        // 唯一标识，如：IA、IA(name)
+	Interface string   `json:"interface"` // 接口
+	Name      string   `json:"name"`      // 别名
+	Lifecycle string   `json:"lifecycle"` // 生命周期
+	Priority  int      `json:"priority"`  // 优先级
+	Implement string   `json:"implement"` // 构造函数或实例的类型
+	Depends   []string `json:"depends"`   // 依赖项（构造函数的入参、实例中需要注入的字段）
+	DependOn  []string `json:"dependOn"`  // 依赖项对应的组件Id
+	Missing   []string `json:"missing"`   // 未注册的依赖项
+}
+
+// GetGraph 获取所有注册组件的依赖关系（按注册顺序）
+func GetGraph() []ComponentGraph {
+	if defContainer == nil {
+		return nil
+	}
+	return defContainer.graph()
+}
+
+// GraphToJson 以JSON格式输出组件的依赖关系
+func GraphToJson() string {
+	data, _ := json.MarshalIndent(GetGraph(), "", "  ")
+	return string(data)
+}
+
+// GraphToDot 以Graphviz DOT格式输出组件的依赖关系
+func GraphToDot() string {
+	var sb strings.Builder
+	sb.WriteString("digraph container {\n")
+	sb.WriteString("  node [shape=box];\n")
+	for _, component := range GetGraph() {
+		sb.WriteString(fmt.Sprintf("  %q [label=%q];\n", component.Id, component.Id+"\n"+component.Lifecycle+"\n"+component.Implement))
+		for _, dependOn := range component.DependOn {
+			sb.WriteString(fmt.Sprintf("  %q -> %q;\n", component.Id, dependOn))
+		}
+		for _, missing := range component.Missing {
+			sb.WriteString(fmt.Sprintf("  %q -> %q [style=dashed, color=red];\n", component.Id, missing))
+		}
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// 获取所有注册组件的依赖关系
+func (r *container) graph() []ComponentGraph {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	lst := []ComponentGraph{}
+	for _, model := range r.component {
+		component := ComponentGraph{
+			Id:        model.String(),
+			Interface: model.interfaceType.String(),
+			Name:      model.name,
+			Lifecycle: model.lifecycle.ToString(),
+			Priority:  model.priority,
+			Implement: model.instanceType.String(),
+			Depends:   []string{},
+			DependOn:  []string{},
+			Missing:   []string{},
+		}
+		if model.target != nil {
+			component.DependOn = append(component.DependOn, model.target.String())
+		}
+		for _, item := range model.dependItems() {
+			component.Depends = append(component.Depends, item.String())
+			dependModels := r.findDepend(model, item, true)
+			if len(dependModels) == 0 && !item.isSlice {
+				component.Missing = append(component.Missing, item.String())
+			}
+			for _, dependModel := range dependModels {
+				component.DependOn = append(component.DependOn, dependModel.String())
+			}
+		}
+		lst = append(lst, component)
+	}
+	return lst
+}
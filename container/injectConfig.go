@@ -0,0 +1,106 @@
+package container
+
+import (
+	"fmt"
+	"github.com/farseer-go/fs/configure"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// 将配置注入到字段，如：`config:"FSS.PullCount"`，配置不存在时保留字段原来的值
+// 支持：基础类型、time.Duration、切片、结构体（子字段使用config标签或字段名称拼接成：FSS.PullCount）
+func injectConfig(fieldVal reflect.Value, configKey string) error {
+	fieldType := fieldVal.Type()
+	switch {
+	case fieldType.Kind() == reflect.Struct:
+		for i := 0; i < fieldType.NumField(); i++ {
+			field := fieldType.Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			subKey := field.Name
+			if tag, exists := field.Tag.Lookup("config"); exists {
+				subKey = tag
+			}
+			if err := injectConfig(fieldVal.Field(i), configKey+"."+subKey); err != nil {
+				return err
+			}
+		}
+	case fieldType.Kind() == reflect.Slice:
+		arr := configure.GetSlice(configKey)
+		// 环境变量等只能以字符串配置时，使用逗号分隔
+		if len(arr) == 0 {
+			if str := configure.GetString(configKey); str != "" {
+				arr = strings.Split(str, ",")
+			}
+		}
+		if len(arr) == 0 {
+			return nil
+		}
+		lst := reflect.MakeSlice(fieldType, 0, len(arr))
+		for _, item := range arr {
+			itemVal := reflect.New(fieldType.Elem()).Elem()
+			if err := setConfigValue(itemVal, configKey, item); err != nil {
+				return err
+			}
+			lst = reflect.Append(lst, itemVal)
+		}
+		fieldVal.Set(lst)
+	default:
+		str := configure.GetString(configKey)
+		if str == "" {
+			return nil
+		}
+		return setConfigValue(fieldVal, configKey, str)
+	}
+	return nil
+}
+
+// 将字符串转换成字段的类型，格式不正确（或超出范围）时返回error
+func setConfigValue(fieldVal reflect.Value, configKey string, str string) error {
+	str = strings.TrimSpace(str)
+	// time.Duration，支持：5s、100ms，纯数字时单位为毫秒
+	if fieldVal.Type() == durationType {
+		duration, err := configure.ParseDuration(str)
+		if err != nil {
+			return fmt.Errorf("%s=%s无法转换成time.Duration", configKey, str)
+		}
+		fieldVal.SetInt(int64(duration))
+		return nil
+	}
+	switch fieldVal.Kind() {
+	case reflect.String:
+		fieldVal.SetString(str)
+	case reflect.Bool:
+		val, err := strconv.ParseBool(str)
+		if err != nil {
+			return fmt.Errorf("%s=%s无法转换成%s", configKey, str, fieldVal.Type().String())
+		}
+		fieldVal.SetBool(val)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		val, err := strconv.ParseInt(str, 10, fieldVal.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%s=%s无法转换成%s", configKey, str, fieldVal.Type().String())
+		}
+		fieldVal.SetInt(val)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		val, err := strconv.ParseUint(str, 10, fieldVal.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%s=%s无法转换成%s", configKey, str, fieldVal.Type().String())
+		}
+		fieldVal.SetUint(val)
+	case reflect.Float32, reflect.Float64:
+		val, err := strconv.ParseFloat(str, fieldVal.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("%s=%s无法转换成%s", configKey, str, fieldVal.Type().String())
+		}
+		fieldVal.SetFloat(val)
+	default:
+		return fmt.Errorf("%s不支持注入到%s类型", configKey, fieldVal.Type().String())
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package container
+
+import (
+	"github.com/farseer-go/fs/configure"
+	"testing"
+	"time"
+)
+
+type IConfigFoo interface{ GetCount() int }
+
+type configFoo struct {
+	Count int `config:"T.Count"`
+}
+
+func (receiver *configFoo) GetCount() int { return receiver.Count }
+
+func TestInjectConfig(t *testing.T) {
+	t.Setenv("T_Count", "9")
+	_ = configure.ReadInConfig()
+	InitContainer()
+	Register(func() IConfigFoo { return &configFoo{Count: 7} })
+	foo, err := TryResolve[IConfigFoo]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if foo.GetCount() != 9 {
+		t.Fatalf("expected 9, got %d", foo.GetCount())
+	}
+}
+
+func TestInjectConfigInvalid(t *testing.T) {
+	t.Setenv("T_Count", "abc")
+	_ = configure.ReadInConfig()
+	InitContainer()
+	Register(func() IConfigFoo { return &configFoo{Count: 7} })
+	if _, err := TryResolve[IConfigFoo](); err == nil {
+		t.Fatal("expected error for unparseable config value")
+	}
+}
+
+type IConfigTimeout interface{ GetTimeout() time.Duration }
+
+type configTimeout struct {
+	Timeout time.Duration `config:"T.Timeout"`
+}
+
+func (receiver *configTimeout) GetTimeout() time.Duration { return receiver.Timeout }
+
+func TestInjectConfigDuration(t *testing.T) {
+	for str, expected := range map[string]time.Duration{"1500": 1500 * time.Millisecond, "5s": 5 * time.Second} {
+		t.Setenv("T_Timeout", str)
+		_ = configure.ReadInConfig()
+		InitContainer()
+		Register(func() IConfigTimeout { return &configTimeout{} })
+		timeout, err := TryResolve[IConfigTimeout]()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if timeout.GetTimeout() != expected {
+			t.Fatalf("%s: expected %s, got %s", str, expected, timeout.GetTimeout())
+		}
+		if configure.GetDuration("T.Timeout") != expected {
+			t.Fatalf("GetDuration(%s): expected %s", str, expected)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package container
+
+import (
+	"fmt"
+	"github.com/farseer-go/fs/exception"
+	"github.com/farseer-go/fs/flog"
+	"github.com/farseer-go/fs/stopwatch"
+	"reflect"
+)
+
+// Interceptor 拦截器，调用invocation.Proceed()执行下一个拦截器（最后一个为真正的方法）
+type Interceptor func(invocation *Invocation) error
+
+// Invocation 一次被拦截的方法调用
+type Invocation struct {
+	InterfaceType reflect.Type  // 被拦截的接口
+	Method        string        // 方法名称
+	interceptors  []Interceptor // 拦截器
+	index         int           // 当前执行到第几个拦截器
+	fn            func() error  // 真正的方法
+}
+
+// Proceed 执行下一个拦截器，所有拦截器执行完后执行真正的方法
+func (receiver *Invocation) Proceed() error {
+	if receiver.index >= len(receiver.interceptors) {
+		return receiver.fn()
+	}
+	interceptor := receiver.interceptors[receiver.index]
+	receiver.index++
+	defer func() { receiver.index-- }()
+	return interceptor(receiver)
+}
+
+// 打印方法名称，如：IA.Get
+func (receiver *Invocation) String() string {
+	return receiver.InterfaceType.String() + "." + receiver.Method
+}
+
+// Intercept 为接口添加拦截器，按添加顺序由外向内执行
+// Go无法在运行时动态实现接口，因此需要通过Decorate包装一层，在包装的方法中调用Invoke：
+//
+//	container.Decorate[IUserRepository](func(inner IUserRepository) IUserRepository { return &userRepositoryProxy{inner} })
+//	func (p *userRepositoryProxy) ToEntity(id int) (user User, err error) {
+//		err = container.Invoke[IUserRepository]("ToEntity", func() error { user, err = p.inner.ToEntity(id); return err })
+//		return
+//	}
+func Intercept[T any](interceptors ...Interceptor) {
+	if defContainer == nil {
+		exception.ThrowRefuseException("请先调用fs.Initialize[Module]()初始化模块")
+	}
+	var t *T
+	interfaceType := reflect.TypeOf(t).Elem()
+
+	defContainer.lock.Lock()
+	defer defContainer.lock.Unlock()
+	defContainer.intercepts[interfaceType] = append(defContainer.intercepts[interfaceType], interceptors...)
+}
+
+// Invoke 经过接口的拦截器后，执行fn
+// method = 方法名称
+func Invoke[T any](method string, fn func() error) error {
+	var t *T
+	interfaceType := reflect.TypeOf(t).Elem()
+
+	var interceptors []Interceptor
+	if defContainer != nil {
+		defContainer.lock.RLock()
+		interceptors = defContainer.intercepts[interfaceType]
+		defContainer.lock.RUnlock()
+	}
+
+	invocation := &Invocation{
+		InterfaceType: interfaceType,
+		Method:        method,
+		interceptors:  interceptors,
+		fn:            fn,
+	}
+	return invocation.Proceed()
+}
+
+// LogInterceptor 打印方法调用及返回的错误
+func LogInterceptor(invocation *Invocation) error {
+	err := invocation.Proceed()
+	if err != nil {
+		flog.Errorf("container：调用%s失败：%s", invocation.String(), err.Error())
+	} else {
+		flog.Debugf("container：调用%s", invocation.String())
+	}
+	return err
+}
+
+// StopwatchInterceptor 打印方法的执行耗时
+func StopwatchInterceptor(invocation *Invocation) error {
+	sw := stopwatch.StartNew()
+	err := invocation.Proceed()
+	flog.Println("耗时：" + sw.GetMillisecondsText() + invocation.String() + "()")
+	return err
+}
+
+// RetryInterceptor 方法返回error或发生异常时重试，最多执行retryCount+1次
+func RetryInterceptor(retryCount int) Interceptor {
+	return func(invocation *Invocation) error {
+		var err error
+		for i := 0; i <= retryCount; i++ {
+			exception.Try(func() {
+				err = invocation.Proceed()
+			}).CatchException(func(exp any) {
+				err = fmt.Errorf("%v", exp)
+			})
+			if err == nil {
+				return nil
+			}
+		}
+		return err
+	}
+}
@@ -0,0 +1,127 @@
+package container
+
+import (
+	"fmt"
+	"github.com/farseer-go/fs/flog"
+	"reflect"
+	"sync"
+)
+
+// Lazy 延迟获取实例，第一次调用Value()时才从容器中获取，之后都返回同一个实例
+// 可以作为构造函数的入参，或带inject标签的字段，如：
+//
+//	UserRepository container.Lazy[IUserRepository] `inject:""`
+type Lazy[T any] struct {
+	value *lazyValue
+}
+
+// 延迟获取的值
+type lazyValue struct {
+	once    sync.Once
+	resolve func() (any, error)
+	ins     any
+	err     error
+}
+
+// Lazy[T]实现的接口，用于反射时识别并创建Lazy[T]
+type lazyInjector interface {
+	lazyElemType() reflect.Type
+	newLazy(resolve func() (any, error)) any
+}
+
+// Value 获取实例，获取失败时打印错误并返回nil
+func (receiver Lazy[T]) Value() T {
+	ins, err := receiver.TryValue()
+	if err != nil {
+		flog.Error(err.Error())
+	}
+	return ins
+}
+
+// TryValue 获取实例，未注册或构造失败时返回error
+func (receiver Lazy[T]) TryValue() (T, error) {
+	var nilResult T
+	if receiver.value == nil {
+		return nilResult, fmt.Errorf("container：Lazy[%s]未注入", receiver.lazyElemType().String())
+	}
+	receiver.value.once.Do(func() {
+		receiver.value.ins, receiver.value.err = receiver.value.resolve()
+	})
+	if receiver.value.err != nil || receiver.value.ins == nil {
+		return nilResult, receiver.value.err
+	}
+	return receiver.value.ins.(T), nil
+}
+
+func (receiver Lazy[T]) lazyElemType() reflect.Type {
+	var t *T
+	return reflect.TypeOf(t).Elem()
+}
+
+func (receiver Lazy[T]) newLazy(resolve func() (any, error)) any {
+	return Lazy[T]{value: &lazyValue{resolve: resolve}}
+}
+
+// 是否为Lazy[interface]类型
+func asLazy(t reflect.Type) (lazyInjector, bool) {
+	if t.Kind() != reflect.Struct {
+		return nil, false
+	}
+	lazy, isOk := reflect.Zero(t).Interface().(lazyInjector)
+	if !isOk || lazy.lazyElemType().Kind() != reflect.Interface {
+		return nil, false
+	}
+	return lazy, true
+}
+
+// 是否为func() interface、func() (interface, error)类型
+func isFactory(t reflect.Type) bool {
+	if t.Kind() != reflect.Func || t.NumIn() != 0 || t.NumOut() == 0 || t.Out(0).Kind() != reflect.Interface {
+		return false
+	}
+	return t.NumOut() == 1 || (t.NumOut() == 2 && t.Out(1) == errorType)
+}
+
+// 延迟获取的接口类型（func() interface、Lazy[interface]）
+func lazyElemType(t reflect.Type) (reflect.Type, bool) {
+	if t.Kind() == reflect.Func && isFactory(t) {
+		return t.Out(0), true
+	}
+	if lazy, isOk := asLazy(t); isOk {
+		return lazy.lazyElemType(), true
+	}
+	return nil, false
+}
+
+// 创建延迟获取的值（func() interface、Lazy[interface]），调用时才通过resolve从容器中获取，遵循注册时的生命周期
+func newLazyValue(t reflect.Type, resolve func(interfaceType reflect.Type) (any, error)) (reflect.Value, bool) {
+	if isFactory(t) {
+		return reflect.MakeFunc(t, func([]reflect.Value) []reflect.Value {
+			ins, err := resolve(t.Out(0))
+			insVal := reflect.Zero(t.Out(0))
+			if ins != nil {
+				insVal = reflect.ValueOf(ins)
+			}
+			// func() (interface, error)
+			if t.NumOut() == 2 {
+				errVal := reflect.Zero(errorType)
+				if err != nil {
+					errVal = reflect.ValueOf(&err).Elem()
+				}
+				return []reflect.Value{insVal, errVal}
+			}
+			if err != nil {
+				flog.Error(err.Error())
+			}
+			return []reflect.Value{insVal}
+		}), true
+	}
+
+	if lazy, isOk := asLazy(t); isOk {
+		elemType := lazy.lazyElemType()
+		return reflect.ValueOf(lazy.newLazy(func() (any, error) {
+			return resolve(elemType)
+		})), true
+	}
+	return reflect.Value{}, false
+}
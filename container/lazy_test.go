@@ -0,0 +1,85 @@
+package container
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+type ILazyFoo interface{ Foo() }
+
+type lazyFoo struct {
+	OnClose func() // 没有inject标签的func字段，不需要注入
+}
+
+func (receiver *lazyFoo) Foo() {}
+
+func TestRegisterInstanceWithFuncField(t *testing.T) {
+	InitContainer()
+	RegisterInstance[ILazyFoo](&lazyFoo{})
+	if _, err := TryResolve[ILazyFoo](); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRegisterConstructorWithFuncParam(t *testing.T) {
+	InitContainer()
+	defer func() {
+		exp := recover()
+		if exp == nil || !strings.Contains(exp.(string), "入参类型必须为") {
+			t.Fatalf("expected param type error, got: %v", exp)
+		}
+	}()
+	Register(func(fn func()) ILazyFoo { return &lazyFoo{OnClose: fn} })
+}
+
+type ILazyA interface{ A() }
+type ILazyB interface{ B() }
+
+type lazyA struct{ b Lazy[ILazyB] }
+type lazyB struct{ a Lazy[ILazyA] }
+
+func (receiver *lazyA) A() {}
+func (receiver *lazyB) B() {}
+
+func TestFactoryCircularDuringConstruction(t *testing.T) {
+	InitContainer()
+	Register(func(b func() (ILazyB, error)) (ILazyA, error) {
+		_, err := b()
+		return &lazyA{}, err
+	})
+	Register(func(a func() (ILazyA, error)) (ILazyB, error) {
+		_, err := a()
+		return &lazyB{}, err
+	})
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := TryResolve[ILazyA]()
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if !errors.Is(err, ErrCircular) {
+			t.Fatalf("expected ErrCircular, got: %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("resolve deadlocked")
+	}
+}
+
+func TestLazyAfterConstruction(t *testing.T) {
+	InitContainer()
+	Register(func(b Lazy[ILazyB]) ILazyA { return &lazyA{b: b} })
+	Register(func(a Lazy[ILazyA]) ILazyB { return &lazyB{a: a} })
+
+	a := Resolve[ILazyA]().(*lazyA)
+	b, err := a.b.TryValue()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.(*lazyB).a.Value() != ILazyA(a) {
+		t.Fatal("lazy should resolve the same singleton")
+	}
+}
@@ -0,0 +1,165 @@
+package container
+
+import (
+	"github.com/farseer-go/fs/container/eumLifecycle"
+	"reflect"
+	"sync"
+)
+
+// 替身（用于单元测试），在重新初始化容器时仍然生效
+type override struct {
+	interfaceType reflect.Type
+	name          string
+	newModel      func() *componentModel
+}
+
+var overrides []override
+var overridesLock sync.Mutex
+
+// Override 使用替身替换指定接口+别名的注册（单例），主要用于单元测试
+// 在fs.Initialize之前或之后调用均可，替身生效后，模块中对同接口同别名的注册会被忽略
+func Override(constructor any, iocName ...string) {
+	name := ""
+	if len(iocName) > 0 {
+		name = iocName[0]
+	}
+	// 提前校验构造函数
+	model := newConstructorModel(constructor, name, eumLifecycle.Single)
+	addOverride(override{
+		interfaceType: model.interfaceType,
+		name:          name,
+		newModel: func() *componentModel {
+			return newConstructorModel(constructor, name, eumLifecycle.Single)
+		},
+	})
+}
+
+// OverrideInstance 使用替身实例替换指定接口+别名的注册，主要用于单元测试
+func OverrideInstance[TInterface any](ins any, iocName ...string) {
+	name := ""
+	if len(iocName) > 0 {
+		name = iocName[0]
+	}
+	model := newInstanceModel((*TInterface)(nil), ins, name, eumLifecycle.Single)
+	addOverride(override{
+		interfaceType: model.interfaceType,
+		name:          name,
+		newModel: func() *componentModel {
+			return newInstanceModel((*TInterface)(nil), ins, name, eumLifecycle.Single)
+		},
+	})
+}
+
+// ResetOverride 清除所有替身（已替换到容器中的注册不会还原，可配合Snapshot使用）
+func ResetOverride() {
+	overridesLock.Lock()
+	defer overridesLock.Unlock()
+	overrides = nil
+}
+
+// Snapshot 保存默认容器及替身的当前状态，返回的函数用于还原，如：
+//
+//	restore := container.Snapshot()
+//	defer restore()
+func Snapshot() (restore func()) {
+	overridesLock.Lock()
+	snapshotOverrides := append([]override{}, overrides...)
+	overridesLock.Unlock()
+
+	var snapshot *container
+	if defContainer != nil {
+		snapshot = defContainer.clone()
+	}
+	return func() {
+		overridesLock.Lock()
+		overrides = snapshotOverrides
+		overridesLock.Unlock()
+		// 每次还原都复制一份，避免还原后的修改影响快照
+		if snapshot != nil {
+			defContainer = snapshot.clone()
+		} else {
+			defContainer = nil
+		}
+	}
+}
+
+// 添加替身，并替换到当前的容器中
+func addOverride(o override) {
+	overridesLock.Lock()
+	defer overridesLock.Unlock()
+	for index := 0; index < len(overrides); index++ {
+		if overrides[index].interfaceType == o.interfaceType && overrides[index].name == o.name {
+			overrides = append(overrides[:index], overrides[index+1:]...)
+			index--
+		}
+	}
+	overrides = append(overrides, o)
+
+	if defContainer != nil {
+		defContainer.applyOverride(o)
+	}
+}
+
+// 将所有替身替换到容器中
+func (r *container) applyOverrides() {
+	overridesLock.Lock()
+	defer overridesLock.Unlock()
+	for _, o := range overrides {
+		r.applyOverride(o)
+	}
+}
+
+// 移除同接口同别名的注册，并注册替身
+func (r *container) applyOverride(o override) {
+	model := o.newModel()
+	model.override = true
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	for _, item := range r.dependency[o.interfaceType] {
+		if item.name == o.name {
+			r.removeComponent(item)
+		}
+	}
+	r.addComponent(model)
+}
+
+// 复制注册信息，实现类模型也会复制一份，并重置已创建（或装饰过）的实例，不包括作用域内的实例
+func (r *container) clone() *container {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	c := NewContainer()
+	c.name = r.name
+
+	models := make(map[*componentModel]*componentModel)
+	cloneModel := func(model *componentModel) *componentModel {
+		if newModel, exists := models[model]; exists {
+			return newModel
+		}
+		newModel := model.clone()
+		models[model] = newModel
+		return newModel
+	}
+	for _, model := range r.component {
+		c.component = append(c.component, cloneModel(model))
+	}
+	for interfaceType, componentModels := range r.dependency {
+		for _, model := range componentModels {
+			c.dependency[interfaceType] = append(c.dependency[interfaceType], cloneModel(model))
+		}
+	}
+	// 绑定到接口的注册，指向复制后的结构体注册
+	for _, newModel := range models {
+		if newModel.target != nil {
+			newModel.target = cloneModel(newModel.target)
+		}
+	}
+
+	for interfaceType, decorators := range r.decorators {
+		c.decorators[interfaceType] = append([]decorator{}, decorators...)
+	}
+	for interfaceType, interceptors := range r.intercepts {
+		c.intercepts[interfaceType] = append([]Interceptor{}, interceptors...)
+	}
+	return c
+}
@@ -0,0 +1,43 @@
+package container
+
+import "testing"
+
+type ISnapshotFoo interface{ Name() string }
+
+type snapshotFoo struct{ name string }
+
+func (receiver *snapshotFoo) Name() string { return receiver.name }
+
+type decoratedFoo struct{ inner ISnapshotFoo }
+
+func (receiver *decoratedFoo) Name() string { return "decorated:" + receiver.inner.Name() }
+
+func TestSnapshotRestore(t *testing.T) {
+	InitContainer()
+	created := 0
+	Register(func() ISnapshotFoo {
+		created++
+		return &snapshotFoo{name: "foo"}
+	})
+	RegisterInstance[ISnapshotFoo](&snapshotFoo{name: "ins"}, "ins")
+
+	restore := Snapshot()
+	Decorate(func(inner ISnapshotFoo) ISnapshotFoo { return &decoratedFoo{inner: inner} })
+	if name := Resolve[ISnapshotFoo]().Name(); name != "decorated:foo" {
+		t.Fatalf("expected decorated:foo, got %s", name)
+	}
+	if name := Resolve[ISnapshotFoo]("ins").Name(); name != "decorated:ins" {
+		t.Fatalf("expected decorated:ins, got %s", name)
+	}
+	restore()
+
+	if name := Resolve[ISnapshotFoo]().Name(); name != "foo" {
+		t.Fatalf("decorator should not survive restore, got %s", name)
+	}
+	if name := Resolve[ISnapshotFoo]("ins").Name(); name != "ins" {
+		t.Fatalf("registered instance should be restored undecorated, got %s", name)
+	}
+	if created != 2 {
+		t.Fatalf("singleton created after Snapshot should not survive restore, created=%d", created)
+	}
+}
@@ -10,6 +10,8 @@ var defContainer *container
 
 func InitContainer() {
 	defContainer = NewContainer()
+	defContainer.applyOverrides()
+	resetConditions()
 }
 
 // Register 注册实例，默认使用单例
@@ -47,3 +49,16 @@ func RegisterInstance[TInterface any](ins any, iocName ...string) {
 	}
 	defContainer.registerInstance((*TInterface)(nil), ins, name, eumLifecycle.Single)
 }
+
+// RegisterScoped 注册实例，使用作用域生命周期（同一个作用域内只创建一次）
+// 只能通过NewScope()创建的作用域获取，在作用域外获取、或被单例依赖时返回ErrScoped
+func RegisterScoped(constructor any, iocName ...string) {
+	if defContainer == nil {
+		exception.ThrowException("请先调用fs.Initialize[Module]()初始化模块")
+	}
+	name := ""
+	if len(iocName) > 0 {
+		name = iocName[0]
+	}
+	defContainer.registerConstructor(constructor, name, eumLifecycle.Scoped)
+}
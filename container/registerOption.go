@@ -0,0 +1,78 @@
+package container
+
+import (
+	"fmt"
+	"github.com/farseer-go/fs/container/eumLifecycle"
+	"github.com/farseer-go/fs/exception"
+)
+
+// RegisterOption 注册选项
+type RegisterOption func(model *componentModel)
+
+// WithName 设置别名
+func WithName(name string) RegisterOption {
+	return func(model *componentModel) {
+		model.name = name
+	}
+}
+
+// WithLifecycle 设置生命周期，默认为单例
+func WithLifecycle(lifecycle eumLifecycle.Enum) RegisterOption {
+	return func(model *componentModel) {
+		model.lifecycle = lifecycle
+	}
+}
+
+// WithPriority 设置优先级，数值越大越优先（默认为0）
+// 同接口同别名有多个注册时，Resolve取优先级最高的；ResolveAll按优先级从高到低返回
+func WithPriority(priority int) RegisterOption {
+	return func(model *componentModel) {
+		model.priority = priority
+	}
+}
+
+// WithReplace 替换同接口同别名的已有注册，用于下游模块有意覆盖上游模块提供的默认实现
+func WithReplace() RegisterOption {
+	return func(model *componentModel) {
+		model.replace = true
+	}
+}
+
+// RegisterWith 使用注册选项注册构造函数，如：
+//
+//	container.RegisterWith(newRedisCache, container.WithReplace(), container.WithPriority(10))
+func RegisterWith(constructor any, options ...RegisterOption) {
+	if defContainer == nil {
+		exception.ThrowRefuseException("请先调用fs.Initialize[Module]()初始化模块")
+	}
+	// 先应用选项，得到别名、生命周期后再校验构造函数
+	option := &componentModel{lifecycle: eumLifecycle.Single}
+	for _, fn := range options {
+		fn(option)
+	}
+	model := newConstructorModel(constructor, option.name, option.lifecycle)
+	for _, fn := range options {
+		fn(model)
+	}
+	defContainer.register(model)
+}
+
+// RegisterInstanceWith 使用注册选项注册实例，实例只能是单例（WithLifecycle设置为其它生命周期时抛出异常）
+func RegisterInstanceWith[TInterface any](ins any, options ...RegisterOption) {
+	if defContainer == nil {
+		exception.ThrowRefuseException("请先调用fs.Initialize[Module]()初始化模块")
+	}
+	option := &componentModel{lifecycle: eumLifecycle.Single}
+	for _, fn := range options {
+		fn(option)
+	}
+	// 同一个实例被共用，其它生命周期会在每次获取时对同一个实例重新注入
+	if option.lifecycle != eumLifecycle.Single {
+		panic(fmt.Sprintf("container：实例注册只能为Single，不能通过WithLifecycle设置为%s", option.lifecycle.ToString()))
+	}
+	model := newInstanceModel((*TInterface)(nil), ins, option.name, eumLifecycle.Single)
+	for _, fn := range options {
+		fn(model)
+	}
+	defContainer.register(model)
+}
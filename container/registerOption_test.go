@@ -0,0 +1,26 @@
+package container
+
+import (
+	"github.com/farseer-go/fs/container/eumLifecycle"
+	"strings"
+	"testing"
+)
+
+type IOptionFoo interface{ Foo() }
+
+type optionFoo struct{}
+
+func (receiver *optionFoo) Foo() {}
+
+func TestRegisterInstanceWithLifecycle(t *testing.T) {
+	InitContainer()
+	RegisterInstanceWith[IOptionFoo](&optionFoo{}, WithLifecycle(eumLifecycle.Single), WithName("single"))
+
+	defer func() {
+		exp := recover()
+		if exp == nil || !strings.Contains(exp.(string), "实例注册只能为Single") {
+			t.Fatalf("expected lifecycle error, got: %v", exp)
+		}
+	}()
+	RegisterInstanceWith[IOptionFoo](&optionFoo{}, WithLifecycle(eumLifecycle.Transient))
+}
@@ -1,22 +1,67 @@
 package container
 
 import (
+	"fmt"
+	"github.com/farseer-go/fs/flog"
 	"reflect"
 )
 
 // Resolve 从容器中获取实例
 // iocName = 别名
 func Resolve[T any](iocName ...string) T {
+	ins, err := TryResolve[T](iocName...)
+	if err != nil {
+		flog.Error(err.Error())
+	}
+	return ins
+}
+
+// TryResolve 从容器中获取实例，未注册或构造失败时返回error
+// iocName = 别名
+func TryResolve[T any](iocName ...string) (T, error) {
+	return tryResolve[T](defContainer, iocName...)
+}
+
+// 从指定的容器中获取实例
+func tryResolve[T any](c *container, iocName ...string) (T, error) {
 	name := ""
 	if len(iocName) > 0 {
 		name = iocName[0]
 	}
 	var t *T
 	interfaceType := reflect.TypeOf(t).Elem()
-	ins := defContainer.resolve(interfaceType, name)
-	if ins == nil {
-		var nilResult T
-		return nilResult
+	var nilResult T
+	ins, err := c.resolve(interfaceType, name, nil)
+	if err != nil || ins == nil {
+		return nilResult, err
+	}
+	return ins.(T), nil
+}
+
+// ResolveAll 从容器中获取接口的所有实现（按优先级从高到低，优先级相同时按注册顺序）
+func ResolveAll[T any]() []T {
+	lst, err := TryResolveAll[T]()
+	if err != nil {
+		flog.Error(err.Error())
+	}
+	return lst
+}
+
+// TryResolveAll 从容器中获取接口的所有实现（按优先级、注册顺序），构造失败时返回error
+func TryResolveAll[T any]() ([]T, error) {
+	return tryResolveAll[T](defContainer)
+}
+
+// 从指定的容器中获取接口的所有实现
+func tryResolveAll[T any](c *container) ([]T, error) {
+	var t *T
+	interfaceType := reflect.TypeOf(t).Elem()
+	if interfaceType.Kind() != reflect.Interface {
+		return nil, fmt.Errorf("container：ResolveAll的泛型只能为interface，当前为%s", interfaceType.String())
+	}
+	lst, err := c.resolveAll(interfaceType, nil)
+	if err != nil {
+		return nil, err
 	}
-	return ins.(T)
+	return lst.Interface().([]T), nil
 }
@@ -0,0 +1,151 @@
+package container
+
+import (
+	"bytes"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// 解析链路，用于检测循环依赖
+type resolveChain []*componentModel
+
+// 链路中是否已存在该实现
+func (c resolveChain) contains(model *componentModel) bool {
+	for _, item := range c {
+		if item == model {
+			return true
+		}
+	}
+	return false
+}
+
+// 追加到链路末尾（返回新的链路，不影响原链路）
+func (c resolveChain) add(model *componentModel) resolveChain {
+	chain := make(resolveChain, len(c), len(c)+1)
+	copy(chain, c)
+	return append(chain, model)
+}
+
+// 打印链路，如：IA -> IB -> IA
+func (c resolveChain) String() string {
+	var names []string
+	for _, model := range c {
+		names = append(names, model.String())
+	}
+	return strings.Join(names, " -> ")
+}
+
+// 构造期间的解析链路，构造期间调用func() interface、Lazy[interface]时沿用当前链路，以检测循环依赖（避免单例的锁重入导致死锁）
+// 构造完成后再调用时，从新的链路开始解析
+type buildingChain struct {
+	lock  sync.RWMutex
+	chain resolveChain
+}
+
+func newBuildingChain(chain resolveChain) *buildingChain {
+	return &buildingChain{chain: chain}
+}
+
+// 当前的解析链路
+func (c *buildingChain) get() resolveChain {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.chain
+}
+
+// 构造完成
+func (c *buildingChain) done() {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.chain = nil
+}
+
+// 当前协程的Id，从协程的堆栈信息中解析，如：goroutine 18 [running]:
+func goroutineId() int64 {
+	buf := make([]byte, 64)
+	buf = bytes.TrimPrefix(buf[:runtime.Stack(buf, false)], []byte("goroutine "))
+	if index := bytes.IndexByte(buf, ' '); index > 0 {
+		buf = buf[:index]
+	}
+	id, _ := strconv.ParseInt(string(buf), 10, 64)
+	return id
+}
+
+// 找出已注册的直接依赖（构造函数的入参、实例中需要注入的字段），调用方需持有锁
+// fallback = 构造函数的入参没有默认别名的实现时，是否使用第一个注册的实现
+func (r *container) dependOn(model *componentModel, fallback bool) []*componentModel {
+	if model.target != nil {
+		return []*componentModel{model.target}
+	}
+	var lst []*componentModel
+	for _, item := range model.dependItems() {
+		// 延迟获取的依赖，不会在创建时形成循环依赖
+		if item.isLazy {
+			continue
+		}
+		if dependModels := r.findDepend(model, item, fallback); dependModels != nil {
+			lst = append(lst, dependModels...)
+		}
+	}
+	return lst
+}
+
+// 找出依赖项对应的实现类，调用方需持有锁
+func (r *container) findDepend(model *componentModel, item dependItem, fallback bool) []*componentModel {
+	componentModels := r.dependency[item.interfaceType]
+	// []interface，依赖所有实现
+	if item.isSlice {
+		return componentModels
+	}
+
+	var dependModel *componentModel
+	switch {
+	// 字段，按inject标签的别名查找
+	case item.field != "":
+		dependModel = findComponent(componentModels, item.name)
+	// 构造函数的入参，优先默认别名，其次第一个注册的实例
+	case fallback:
+		dependModel = findDefaultOrFirstComponent(componentModels)
+	default:
+		dependModel = findComponent(componentModels, "")
+	}
+	if dependModel == nil {
+		return nil
+	}
+	return []*componentModel{dependModel}
+}
+
+// 沿着依赖关系查找循环依赖，找到时返回完整的链路
+// 注册时（fallback=false）注册信息还不完整，后续注册的默认实现会改变依赖关系，因此不沿着“第一个注册的实现”查找，交给Validate、Resolve时检测
+func (r *container) findCircular(model *componentModel, chain resolveChain, fallback bool) resolveChain {
+	if chain.contains(model) {
+		return chain.add(model)
+	}
+	chain = chain.add(model)
+	for _, dependModel := range r.dependOn(model, fallback) {
+		if circularChain := r.findCircular(dependModel, chain, fallback); circularChain != nil {
+			return circularChain
+		}
+	}
+	return nil
+}
+
+// 循环依赖的标识（环上的组件排序后拼接），同一个环从不同的组件开始查找时标识相同
+func (c resolveChain) circularKey() string {
+	if len(c) == 0 {
+		return ""
+	}
+	last := c[len(c)-1]
+	var names []string
+	for index := len(c) - 2; index >= 0; index-- {
+		names = append(names, c[index].String())
+		if c[index] == last {
+			break
+		}
+	}
+	sort.Strings(names)
+	return strings.Join(names, ",")
+}
@@ -0,0 +1,86 @@
+package container
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type IResolveFoo interface{ Foo() }
+
+type resolveFoo struct{}
+
+func (receiver *resolveFoo) Foo() {}
+
+// 使用go test -race运行，同时检查并发获取单例时的数据竞争
+func TestResolveSingleConcurrently(t *testing.T) {
+	InitContainer()
+	var created int32
+	Register(func() IResolveFoo {
+		atomic.AddInt32(&created, 1)
+		time.Sleep(10 * time.Millisecond)
+		return &resolveFoo{}
+	})
+
+	const count = 100
+	lst := make([]IResolveFoo, count)
+	var wg sync.WaitGroup
+	for i := 0; i < count; i++ {
+		wg.Add(1)
+		go func(index int) {
+			defer wg.Done()
+			lst[index] = Resolve[IResolveFoo]()
+		}(i)
+	}
+	wg.Wait()
+
+	if created != 1 {
+		t.Fatalf("singleton constructed %d times", created)
+	}
+	for _, foo := range lst {
+		if foo == nil || foo != lst[0] {
+			t.Fatal("all resolves should return the same singleton")
+		}
+	}
+}
+
+type IReentrantA interface{ A() }
+type IReentrantB interface{ B() }
+
+type reentrantA struct{}
+type reentrantB struct{}
+
+func (receiver *reentrantA) A() {}
+func (receiver *reentrantB) B() {}
+
+// 构造函数中直接调用Resolve，又获取到正在创建的单例时，返回ErrCircular（而不是死锁）
+func TestResolveReentrantSingle(t *testing.T) {
+	InitContainer()
+	Register(func() (IReentrantA, error) {
+		if _, err := TryResolve[IReentrantB](); err != nil {
+			return nil, err
+		}
+		return &reentrantA{}, nil
+	})
+	Register(func(a IReentrantA) IReentrantB { return &reentrantB{} })
+
+	result := make(chan error, 1)
+	go func() {
+		// 失败后再次获取，也不会被阻塞
+		TryResolve[IReentrantA]()
+		_, err := TryResolve[IReentrantA]()
+		result <- err
+	}()
+	select {
+	case err := <-result:
+		expected := "container：存在循环依赖：container.IReentrantA -> container.IReentrantB -> container.IReentrantA"
+		if !errors.Is(err, ErrCircular) || !strings.HasSuffix(err.Error(), expected) {
+			t.Fatalf("expected %s, got: %v", expected, err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("resolve deadlocked")
+	}
+}
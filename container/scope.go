@@ -0,0 +1,50 @@
+package container
+
+import (
+	"github.com/farseer-go/fs/exception"
+	"github.com/farseer-go/fs/flog"
+)
+
+// Scope 作用域（如：一次http请求、一次后台任务）
+// 通过RegisterScoped注册的组件，在同一个作用域内只创建一次，作用域结束后释放
+type Scope struct {
+	container *container
+}
+
+// NewScope 从默认容器中创建一个作用域
+func NewScope() *Scope {
+	if defContainer == nil {
+		exception.ThrowRefuseException("请先调用fs.Initialize[Module]()初始化模块")
+	}
+	return &Scope{container: defContainer.newScope()}
+}
+
+// Close 结束作用域，按创建顺序的倒序释放作用域内的实例
+func (s *Scope) Close() {
+	s.container.dispose(false)
+}
+
+// ResolveScope 从作用域中获取实例
+// iocName = 别名
+func ResolveScope[T any](scope *Scope, iocName ...string) T {
+	ins, err := TryResolveScope[T](scope, iocName...)
+	if err != nil {
+		flog.Error(err.Error())
+	}
+	return ins
+}
+
+// TryResolveScope 从作用域中获取实例，未注册或构造失败时返回error
+// iocName = 别名
+func TryResolveScope[T any](scope *Scope, iocName ...string) (T, error) {
+	return tryResolve[T](scope.container, iocName...)
+}
+
+// ResolveAllScope 从作用域中获取接口的所有实现（按优先级、注册顺序）
+func ResolveAllScope[T any](scope *Scope) []T {
+	lst, err := tryResolveAll[T](scope.container)
+	if err != nil {
+		flog.Error(err.Error())
+	}
+	return lst
+}
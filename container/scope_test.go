@@ -0,0 +1,73 @@
+package container
+
+import (
+	"errors"
+	"testing"
+)
+
+type IScopedFoo interface{ Foo() }
+type IScopedBar interface{ Bar() }
+
+type scopedFoo struct{ closed bool }
+type scopedBar struct{ foo IScopedFoo }
+
+func (receiver *scopedFoo) Foo()   {}
+func (receiver *scopedFoo) Close() { receiver.closed = true }
+func (receiver *scopedBar) Bar()   {}
+
+func TestScopedOutsideScope(t *testing.T) {
+	InitContainer()
+	RegisterScoped(func() IScopedFoo { return &scopedFoo{} })
+	if _, err := TryResolve[IScopedFoo](); !errors.Is(err, ErrScoped) {
+		t.Fatalf("expected ErrScoped, got: %v", err)
+	}
+}
+
+func TestSingleDependsOnScoped(t *testing.T) {
+	InitContainer()
+	RegisterScoped(func() IScopedFoo { return &scopedFoo{} })
+	Register(func(foo IScopedFoo) IScopedBar { return &scopedBar{foo: foo} })
+
+	scope := NewScope()
+	defer scope.Close()
+	if _, err := TryResolveScope[IScopedBar](scope); !errors.Is(err, ErrScoped) {
+		t.Fatalf("expected ErrScoped, got: %v", err)
+	}
+	var validateErr *ValidateError
+	if err := Validate(); !errors.As(err, &validateErr) || !errors.Is(validateErr.Errors[0], ErrScoped) {
+		t.Fatalf("expected Validate to report ErrScoped, got: %v", err)
+	}
+}
+
+func TestScopeIdentity(t *testing.T) {
+	InitContainer()
+	RegisterScoped(func() IScopedFoo { return &scopedFoo{} })
+
+	scope1 := NewScope()
+	defer scope1.Close()
+	scope2 := NewScope()
+	defer scope2.Close()
+
+	foo1 := ResolveScope[IScopedFoo](scope1)
+	if foo1 == nil || foo1 != ResolveScope[IScopedFoo](scope1) {
+		t.Fatal("the same scope should return the same instance")
+	}
+	if foo1 == ResolveScope[IScopedFoo](scope2) {
+		t.Fatal("different scopes should return different instances")
+	}
+}
+
+func TestScopeClose(t *testing.T) {
+	InitContainer()
+	RegisterScoped(func() IScopedFoo { return &scopedFoo{} })
+
+	scope := NewScope()
+	foo := ResolveScope[IScopedFoo](scope).(*scopedFoo)
+	scope.Close()
+	if !foo.closed {
+		t.Fatal("Close() should dispose instances created in the scope")
+	}
+	if ResolveScope[IScopedFoo](scope) == IScopedFoo(foo) {
+		t.Fatal("a closed scope should create a new instance")
+	}
+}
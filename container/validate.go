@@ -0,0 +1,90 @@
+package container
+
+import (
+	"fmt"
+	"github.com/farseer-go/fs/container/eumLifecycle"
+	"strings"
+)
+
+// ValidateError 依赖关系校验不通过
+type ValidateError struct {
+	Errors []error // 所有发现的问题
+}
+
+func (e *ValidateError) Error() string {
+	var lst []string
+	for _, err := range e.Errors {
+		lst = append(lst, err.Error())
+	}
+	return "container：依赖关系校验不通过，共" + fmt.Sprint(len(e.Errors)) + "个问题：\n" + strings.Join(lst, "\n")
+}
+
+// Validate 校验所有注册的组件：构造函数的入参、实例中需要注入的字段（含inject别名）是否都已注册，是否存在循环依赖，以及单例是否依赖了Scoped组件
+// 会一次性返回所有问题，没有问题时返回nil
+func Validate() error {
+	if defContainer == nil {
+		return fmt.Errorf("container：请先调用fs.Initialize[Module]()初始化模块")
+	}
+	return defContainer.validate()
+}
+
+// 校验所有注册的组件
+func (r *container) validate() error {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	var errs []error
+	circulars := make(map[string]bool)
+	for _, model := range r.component {
+		// 注册的实例直接返回，不会注入字段，也不会依赖其它组件
+		if model.isInstance() {
+			continue
+		}
+		for _, item := range model.dependItems() {
+			// []interface允许没有实现
+			if item.isSlice {
+				continue
+			}
+			if r.findDepend(model, item, true) == nil {
+				errs = append(errs, fmt.Errorf("container：%s依赖的%s%w", model.String(), item.String(), ErrNotRegistered))
+			}
+		}
+
+		// 单例（包括间接通过Transient）依赖了Scoped组件
+		if model.lifecycle == eumLifecycle.Single {
+			if scopedModel := r.findScoped(model, make(map[*componentModel]bool)); scopedModel != nil {
+				errs = append(errs, fmt.Errorf("container：单例%s依赖的%s%w", model.String(), scopedModel.String(), ErrScoped))
+			}
+		}
+
+		// 同一个循环只报告一次
+		if chain := r.findCircular(model, nil, true); chain != nil && !circulars[chain.circularKey()] {
+			circulars[chain.circularKey()] = true
+			errs = append(errs, fmt.Errorf("container：%w：%s", ErrCircular, chain.String()))
+		}
+	}
+
+	if len(errs) > 0 {
+		return &ValidateError{Errors: errs}
+	}
+	return nil
+}
+
+// 沿着依赖关系（不经过其它单例）查找Scoped组件，调用方需持有锁
+func (r *container) findScoped(model *componentModel, visited map[*componentModel]bool) *componentModel {
+	visited[model] = true
+	for _, dependModel := range r.dependOn(model, true) {
+		if visited[dependModel] {
+			continue
+		}
+		switch dependModel.lifecycle {
+		case eumLifecycle.Scoped:
+			return dependModel
+		case eumLifecycle.Transient:
+			if scopedModel := r.findScoped(dependModel, visited); scopedModel != nil {
+				return scopedModel
+			}
+		}
+	}
+	return nil
+}
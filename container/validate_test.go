@@ -0,0 +1,23 @@
+package container
+
+import (
+	"testing"
+)
+
+// 注册的实例中已赋值的接口字段（如io.Writer）不需要注册
+func TestValidateRegisteredInstance(t *testing.T) {
+	InitContainer()
+	RegisterInstance[IInstanceA](&instanceA{B: &instanceB{}})
+	if err := Validate(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+
+	// 字段的接口为Scoped组件时，也不是依赖项
+	RegisterScoped(func() IInstanceB { return &instanceB{} })
+	if err := Validate(); err != nil {
+		t.Fatalf("expected no error, got: %v", err)
+	}
+	if _, err := TryResolve[IInstanceA](); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,101 @@
+package fs
+
+import (
+	"context"
+	"fmt"
+	"github.com/farseer-go/fs/flog"
+	"github.com/farseer-go/fs/stopwatch"
+	"reflect"
+	"runtime"
+	"sort"
+	"strconv"
+)
+
+// 启动完后执行的函数
+type initCallback struct {
+	name  string       // 名称
+	order int          // 执行顺序，从小到大执行
+	async bool         // 是否异步执行
+	fn    func() error // 执行的函数
+}
+
+// CallbackOption AddInitCallbackWith的可选项
+type CallbackOption func(*initCallback)
+
+// WithCallbackOrder 设置执行顺序（默认0），从小到大执行，相同时按添加的顺序执行
+func WithCallbackOrder(order int) CallbackOption {
+	return func(callback *initCallback) {
+		callback.order = order
+	}
+}
+
+// WithCallbackAsync 异步执行（如预热任务），不阻塞启动，返回error时只打印日志，Run退出时会等待其结束
+func WithCallbackAsync() CallbackOption {
+	return func(callback *initCallback) {
+		callback.async = true
+	}
+}
+
+var callbackFnList []*initCallback
+
+// AddInitCallback 添加框架启动完后执行的函数
+func AddInitCallback(fn func()) {
+	AddInitCallbackWith(runtime.FuncForPC(reflect.ValueOf(fn).Pointer()).Name(), func() error {
+		fn()
+		return nil
+	})
+}
+
+// AddInitCallbackWith 添加框架启动完后执行的函数，返回error时启动失败（异步执行的函数除外）
+func AddInitCallbackWith(name string, fn func() error, options ...CallbackOption) {
+	callback := &initCallback{name: name, fn: fn}
+	for _, option := range options {
+		option(callback)
+	}
+	callbackFnList = append(callbackFnList, callback)
+}
+
+// 按顺序执行启动完后的函数，同步执行的函数返回error时，不再执行后面的函数
+func runInitCallbacks() error {
+	callbacks := make([]*initCallback, len(callbackFnList))
+	copy(callbacks, callbackFnList)
+	sort.SliceStable(callbacks, func(i, j int) bool {
+		return callbacks[i].order < callbacks[j].order
+	})
+
+	reports := make([]CallbackReport, len(callbacks))
+	for index, callback := range callbacks {
+		reports[index] = CallbackReport{Name: callback.name, Order: callback.order, Async: callback.async}
+	}
+	reportLock.Lock()
+	callbackReports = reports
+	reportLock.Unlock()
+
+	for index, callback := range callbacks {
+		if callback.async {
+			index, callback := index, callback
+			AddWorker(callback.name, func(ctx context.Context) {
+				sw := stopwatch.StartNew()
+				err := callback.fn()
+				setCallbackReport(index, sw.ElapsedMilliseconds(), err)
+				if err != nil {
+					flog.Errorf("异步运行%d：%s，执行失败：%s", index+1, callback.name, err.Error())
+					return
+				}
+				flog.Println("异步运行" + strconv.Itoa(index+1) + "：" + callback.name + "，共耗时：" + sw.GetMillisecondsText())
+			})
+			continue
+		}
+
+		sw := stopwatch.StartNew()
+		err := callback.fn()
+		setCallbackReport(index, sw.ElapsedMilliseconds(), err)
+		if err != nil {
+			flog.Errorf("运行%d：%s，执行失败：%s", index+1, callback.name, err.Error())
+			return fmt.Errorf("fs：启动完后执行的函数%s失败：%w", callback.name, err)
+		}
+		flog.Println("运行" + strconv.Itoa(index+1) + "：" + callback.name + "，共耗时：" + sw.GetMillisecondsText())
+		flog.Println("---------------------------------------")
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package fs
 
 import (
 	"github.com/farseer-go/fs/configure"
+	"github.com/farseer-go/fs/container"
 	"github.com/farseer-go/fs/dateTime"
 	"github.com/farseer-go/fs/flog"
 	"github.com/farseer-go/fs/modules"
@@ -11,7 +12,6 @@ import (
 	"github.com/farseer-go/fs/stopwatch"
 	"math/rand"
 	"os"
-	"reflect"
 	"strconv"
 	"strings"
 	"time"
@@ -23,6 +23,9 @@ var StartupAt dateTime.DateTime
 // AppName 应用名称
 var AppName string
 
+// Environment 运行环境
+var Environment string
+
 // HostName 主机名称
 var HostName string
 
@@ -38,13 +41,23 @@ var ProcessId int
 // 依赖的模块
 var dependModules []modules.FarseerModule
 
-var callbackFnList []func()
-
-// Initialize 初始化框架
-func Initialize[TModule modules.FarseerModule](appName string) {
+// Initialize 初始化框架，模块存在循环依赖、模块启动失败、容器依赖关系校验不通过、启动完后执行的函数返回error时返回error
+func Initialize[TModule modules.FarseerModule](appName string, options ...InitializeOption) (err error) {
 	sw := stopwatch.StartNew()
+	defer func() {
+		buildStartupReport(sw.ElapsedMilliseconds(), err)
+	}()
+
+	initOptions := newInitializeOptions(options)
+	if initOptions.workDir != "" {
+		if err = os.Chdir(initOptions.workDir); err != nil {
+			flog.Errorf("切换工作目录%s失败：%s", initOptions.workDir, err.Error())
+			return err
+		}
+	}
 
 	AppName = appName
+	Environment = initOptions.environment
 	ProcessId = os.Getppid()
 	HostName, _ = os.Hostname()
 	rand.Seed(time.Now().UnixNano())
@@ -53,58 +66,124 @@ func Initialize[TModule modules.FarseerModule](appName string) {
 	AppId = snowflake.GenerateId()
 	AppIp = net.GetIp()
 
-	flog.Println("应用名称：", flog.Colors[2](AppName))
-	flog.Println("主机名称：", flog.Colors[2](HostName))
-	flog.Println("系统时间：", flog.Colors[2](StartupAt.ToString("yyyy-MM-dd hh:mm:ss")))
-	flog.Println("  进程ID：", flog.Colors[2](ProcessId))
-	flog.Println("  应用ID：", flog.Colors[2](AppId))
-	flog.Println("  应用IP：", flog.Colors[2](AppIp))
-	showComponentLog()
-	flog.Println("---------------------------------------")
+	if !initOptions.quiet {
+		flog.Println("应用名称：", flog.Colors[2](AppName))
+		flog.Println("主机名称：", flog.Colors[2](HostName))
+		flog.Println("系统时间：", flog.Colors[2](StartupAt.ToString("yyyy-MM-dd hh:mm:ss")))
+		flog.Println("  进程ID：", flog.Colors[2](ProcessId))
+		flog.Println("  应用ID：", flog.Colors[2](AppId))
+		flog.Println("  应用IP：", flog.Colors[2](AppIp))
+		if Environment != "" {
+			flog.Println("运行环境：", flog.Colors[2](Environment))
+		}
+	}
+	if readConfig(initOptions.getConfigFiles()) && !initOptions.quiet {
+		showComponentLog()
+	}
+	if !initOptions.quiet {
+		flog.Println("---------------------------------------")
+	}
 
 	var startupModule TModule
 	flog.Println("加载模块...")
-	dependModules = modules.Distinct(modules.GetDependModule(startupModule))
+	ctx := newRootContext()
+	loadModules, err := modules.LoadDependModule(startupModule)
+	if err != nil {
+		flog.Error(err.Error())
+		abortStartup(nil)
+		return err
+	}
+	dependModules = modules.Distinct(loadModules)
 	flog.Println("加载完毕，共加载 " + strconv.Itoa(len(dependModules)) + " 个模块")
 	flog.Println("---------------------------------------")
 
-	modules.StartModules(dependModules)
+	// 启动失败时，已初始化的模块已经被关闭
+	if err = modules.StartModulesContext(ctx, dependModules); err != nil {
+		abortStartup(nil)
+		return err
+	}
 	flog.Println("初始化完毕，共耗时：" + sw.GetMillisecondsText())
 	flog.Println("---------------------------------------")
 
-	if len(callbackFnList) > 0 {
-		for index, fn := range callbackFnList {
-			sw.Restart()
-			fn()
-			flog.Println("运行" + strconv.Itoa(index+1) + "：" + reflect.TypeOf(fn).String() + "，共耗时：" + sw.GetMillisecondsText())
-			flog.Println("---------------------------------------")
+	// 校验容器的依赖关系
+	if configure.GetBool("Container.Validate") {
+		if err = validateContainer(); err != nil {
+			abortStartup(dependModules)
+			return err
 		}
 	}
+
+	// 打印模块及容器的依赖关系图
+	if configure.GetBool("Graph.Print") {
+		printGraph(startupModule)
+	}
+
+	// 启动完后执行的函数
+	if err = runInitCallbacks(); err != nil {
+		abortStartup(dependModules)
+		return err
+	}
+	return nil
+}
+
+// 读取配置文件，读取失败时返回false
+func readConfig(configFiles []string) bool {
+	err := configure.ReadInConfigFile(configFiles...)
+	if err != nil { // 捕获读取中遇到的error
+		flog.Errorf("配置[%s]读取时发生错误: %s \n", strings.Join(configFiles, ","), err)
+		return false
+	}
+	return true
 }
 
 // 组件日志
 func showComponentLog() {
-	err := configure.ReadInConfig()
-	if err != nil { // 捕获读取中遇到的error
-		flog.Errorf("配置[farseer.yaml]读取时发生错误: %s \n", err)
-	} else {
-		logConfig := configure.GetSubNodes("Log.Component")
-		var logSets []string
-		for k, v := range logConfig {
-			if v == "true" {
-				logSets = append(logSets, k)
-			}
+	logConfig := configure.GetSubNodes("Log.Component")
+	var logSets []string
+	for k, v := range logConfig {
+		if v == "true" {
+			logSets = append(logSets, k)
 		}
-		flog.Println("日志开关：", flog.Colors[2](strings.Join(logSets, " ")))
 	}
+	flog.Println("日志开关：", flog.Colors[2](strings.Join(logSets, " ")))
+}
+
+// 校验容器的依赖关系
+func validateContainer() error {
+	if err := container.Validate(); err != nil {
+		flog.Error(err.Error())
+		return err
+	}
+	flog.Println("容器依赖关系校验通过")
+	flog.Println("---------------------------------------")
+	return nil
+}
+
+// 打印模块及容器的依赖关系图，Graph.Format = dot（默认）、json
+func printGraph(startupModule modules.FarseerModule) {
+	if strings.EqualFold(configure.GetString("Graph.Format"), "json") {
+		flog.Println("模块依赖关系：\n" + modules.GraphToJson(startupModule))
+		flog.Println("容器依赖关系：\n" + container.GraphToJson())
+	} else {
+		flog.Println("模块依赖关系：\n" + modules.GraphToDot(startupModule))
+		flog.Println("容器依赖关系：\n" + container.GraphToDot())
+	}
+	flog.Println("---------------------------------------")
 }
 
 // Exit 应用退出
 func Exit() {
+	cancelRootContext()
 	modules.ShutdownModules(dependModules)
+	container.Dispose()
 }
 
-// AddInitCallback 添加框架启动完后执行的函数
-func AddInitCallback(fn func()) {
-	callbackFnList = append(callbackFnList, fn)
+// 启动失败时释放资源：取消根上下文、关闭已启动的模块（startedModules）、释放容器创建的组件
+func abortStartup(startedModules []modules.FarseerModule) {
+	cancelRootContext()
+	if len(startedModules) > 0 {
+		modules.ShutdownModules(startedModules)
+	}
+	container.Dispose()
+	dependModules = nil
 }
@@ -0,0 +1,71 @@
+package fs
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// fs.Initialize的可选项
+type initializeOptions struct {
+	configFiles []string // 配置文件，后面的文件优先级更高
+	environment string   // 运行环境
+	workDir     string   // 工作目录
+	quiet       bool     // 静默模式，不打印启动信息
+}
+
+// InitializeOption fs.Initialize的可选项
+type InitializeOption func(*initializeOptions)
+
+// WithConfigFile 设置配置文件（默认：./farseer.yaml），多个配置文件时，后面的文件优先级更高
+func WithConfigFile(configFiles ...string) InitializeOption {
+	return func(options *initializeOptions) {
+		options.configFiles = configFiles
+	}
+}
+
+// WithEnvironment 设置运行环境（如：Development、Production），存在farseer.{env}.yaml时，覆盖farseer.yaml中的配置
+func WithEnvironment(environment string) InitializeOption {
+	return func(options *initializeOptions) {
+		options.environment = environment
+	}
+}
+
+// WithWorkDir 设置工作目录，相对路径的配置文件以工作目录为准
+func WithWorkDir(workDir string) InitializeOption {
+	return func(options *initializeOptions) {
+		options.workDir = workDir
+	}
+}
+
+// WithQuiet 静默模式，不打印应用名称、主机名称等启动信息，用于命令行工具、单元测试
+func WithQuiet() InitializeOption {
+	return func(options *initializeOptions) {
+		options.quiet = true
+	}
+}
+
+func newInitializeOptions(options []InitializeOption) initializeOptions {
+	initOptions := initializeOptions{configFiles: []string{"./farseer.yaml"}}
+	for _, option := range options {
+		option(&initOptions)
+	}
+	return initOptions
+}
+
+// 需要读取的配置文件，每个配置文件之后是对应环境的配置文件（存在时），如：farseer.yaml、farseer.Production.yaml
+func (r initializeOptions) getConfigFiles() []string {
+	if r.environment == "" {
+		return r.configFiles
+	}
+	var configFiles []string
+	for _, configFile := range r.configFiles {
+		configFiles = append(configFiles, configFile)
+		ext := filepath.Ext(configFile)
+		envConfigFile := strings.TrimSuffix(configFile, ext) + "." + r.environment + ext
+		if _, err := os.Stat(envConfigFile); err == nil {
+			configFiles = append(configFiles, envConfigFile)
+		}
+	}
+	return configFiles
+}
@@ -0,0 +1,42 @@
+package fs
+
+import (
+	"errors"
+	"github.com/farseer-go/fs/modules"
+	"testing"
+)
+
+type testModule struct{}
+
+func (testModule) DependsModule() []modules.FarseerModule { return nil }
+func (testModule) PreInitialize()                         {}
+func (testModule) Initialize()                            {}
+func (testModule) PostInitialize()                        {}
+func (testModule) Shutdown()                              {}
+
+func TestInitializeAfterStartupFailure(t *testing.T) {
+	fail := true
+	AddInitCallbackWith("fail", func() error {
+		if fail {
+			return errors.New("fail")
+		}
+		return nil
+	})
+	defer func() { callbackFnList = nil }()
+
+	if err := Initialize[testModule]("test", WithQuiet()); err == nil {
+		t.Fatal("expected startup error")
+	}
+	if Context().Err() == nil {
+		t.Fatal("root context should be canceled after startup failure")
+	}
+
+	fail = false
+	if err := Initialize[testModule]("test", WithQuiet()); err != nil {
+		t.Fatal(err)
+	}
+	if Context().Err() != nil {
+		t.Fatal("root context should be recreated by Initialize")
+	}
+	Exit()
+}
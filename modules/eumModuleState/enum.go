@@ -0,0 +1,27 @@
+package eumModuleState
+
+type Enum int
+
+const (
+	Unloaded     Enum = iota // 未加载
+	Initializing             // 初始化中
+	Ready                    // 就绪
+	Degraded                 // 降级（健康检查未通过）
+	Stopped                  // 已停止（启动失败或已关闭）
+)
+
+func (r Enum) ToString() string {
+	switch r {
+	case Unloaded:
+		return "Unloaded"
+	case Initializing:
+		return "Initializing"
+	case Ready:
+		return "Ready"
+	case Degraded:
+		return "Degraded"
+	case Stopped:
+		return "Stopped"
+	}
+	return "Unloaded"
+}
@@ -16,6 +16,8 @@ func (module FarseerKernelModule) PreInitialize() {
 }
 
 func (module FarseerKernelModule) Initialize() {
+	// 所有模块PreInitialize完成后，判断条件注册
+	container.ApplyConditions()
 }
 
 func (module FarseerKernelModule) PostInitialize() {
@@ -1,5 +1,7 @@
 package modules
 
+import "context"
+
 type FarseerModule interface {
 	// DependsModule 依赖的模块
 	DependsModule() []FarseerModule
@@ -12,3 +14,22 @@ type FarseerModule interface {
 	// Shutdown 应用关闭之前先关闭模块
 	Shutdown()
 }
+
+// FarseerModuleWithContext 可选的扩展模块接口，实现后StartModules调用带ctx的方法代替PreInitialize、Initialize、PostInitialize
+// 返回error时中止启动，并关闭已初始化的模块
+type FarseerModuleWithContext interface {
+	FarseerModule
+	// PreInitializeContext 预初始化
+	PreInitializeContext(ctx context.Context) error
+	// InitializeContext 初始化
+	InitializeContext(ctx context.Context) error
+	// PostInitializeContext 初始化之后
+	PostInitializeContext(ctx context.Context) error
+}
+
+// FarseerModuleWithHealthCheck 可选的健康检查接口，Readiness时调用，返回error时模块状态为Degraded
+type FarseerModuleWithHealthCheck interface {
+	FarseerModule
+	// HealthCheck 健康检查
+	HealthCheck(ctx context.Context) error
+}
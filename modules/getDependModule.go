@@ -1,85 +1,437 @@
 package modules
 
 import (
+	"context"
+	"fmt"
+	"github.com/farseer-go/fs/configure"
+	"github.com/farseer-go/fs/exception"
 	"github.com/farseer-go/fs/flog"
+	"github.com/farseer-go/fs/modules/eumModuleState"
 	"github.com/farseer-go/fs/stopwatch"
 	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
 )
 
-// GetDependModule 查找模块的依赖
+// GetDependModule 查找模块的依赖，按依赖关系拓扑排序（被依赖的模块排在前面），存在循环依赖时抛出异常
 func GetDependModule(module ...FarseerModule) []FarseerModule {
-	var modules []FarseerModule
-	for _, farseerModule := range module {
-		dependsModules := farseerModule.DependsModule()
-		if dependsModules != nil {
-			modules = append(modules, GetDependModule(dependsModules...)...)
+	modules, err := LoadDependModule(module...)
+	if err != nil {
+		panic(err.Error())
+	}
+	return modules
+}
+
+// LoadDependModule 查找模块的依赖，按依赖关系拓扑排序（被依赖的模块排在前面）
+// 跳过Modules.Disabled中禁用的模块（及只被禁用模块依赖的模块），存在循环依赖、或其它模块依赖了禁用的模块时返回error
+func LoadDependModule(module ...FarseerModule) ([]FarseerModule, error) {
+	disabled := configure.GetSlice("Modules.Disabled")
+	// 环境变量等只能以字符串配置时，使用逗号分隔
+	if len(disabled) == 0 {
+		if str := configure.GetString("Modules.Disabled"); str != "" {
+			disabled = strings.Split(str, ",")
 		}
+	}
+	for _, name := range disabled {
+		if isModuleName(reflect.TypeOf(FarseerKernelModule{}), name) {
+			return nil, fmt.Errorf("modules：不能禁用模块%s", name)
+		}
+	}
 
+	modules, err := sortModules(func(moduleType reflect.Type) bool {
+		for _, name := range disabled {
+			if isModuleName(moduleType, name) {
+				return true
+			}
+		}
+		return false
+	}, module...)
+	if err != nil {
+		return nil, err
+	}
+	for _, farseerModule := range modules {
 		flog.Println("加载模块：" + flog.Colors[5](reflect.TypeOf(farseerModule).String()) + "")
+	}
+	return modules, nil
+}
+
+// TopologicalSort 按DependsModule拓扑排序（被依赖的模块排在前面），以模块的类型作为唯一标识
+// 同一层的依赖按DependsModule返回的顺序，结果是确定的；存在循环依赖时返回完整的链路
+func TopologicalSort(module ...FarseerModule) ([]FarseerModule, error) {
+	return sortModules(nil, module...)
+}
+
+// 拓扑排序，跳过isDisabled的模块（入口模块可以依赖禁用的模块，其它模块依赖禁用的模块时返回error）
+func sortModules(isDisabled func(moduleType reflect.Type) bool, module ...FarseerModule) ([]FarseerModule, error) {
+	var modules []FarseerModule
+	visited := make(map[reflect.Type]bool)
+	skipped := make(map[reflect.Type]bool) // 已跳过的禁用模块
+	var visiting []reflect.Type            // 当前的依赖链路
+
+	var visit func(farseerModule FarseerModule) error
+	visit = func(farseerModule FarseerModule) error {
+		moduleType := reflect.TypeOf(farseerModule)
+		if isDisabled != nil && isDisabled(moduleType) {
+			// 入口模块被禁用
+			if len(visiting) == 0 {
+				return fmt.Errorf("modules：不能禁用模块%s", moduleType.String())
+			}
+			// 入口模块之外的模块依赖了禁用的模块
+			if len(visiting) > 1 {
+				return fmt.Errorf("modules：模块%s依赖了已禁用的模块%s", visiting[len(visiting)-1].String(), moduleType.String())
+			}
+			if !skipped[moduleType] {
+				skipped[moduleType] = true
+				flog.Println("跳过模块：" + flog.Colors[5](moduleType.String()) + "（已禁用）")
+			}
+			return nil
+		}
+		if visited[moduleType] {
+			return nil
+		}
+		for index, item := range visiting {
+			if item == moduleType {
+				var chain []string
+				for _, chainType := range append(visiting[index:], moduleType) {
+					chain = append(chain, chainType.String())
+				}
+				return fmt.Errorf("modules：存在循环依赖：%s", strings.Join(chain, " -> "))
+			}
+		}
+
+		visiting = append(visiting, moduleType)
+		for _, dependsModule := range farseerModule.DependsModule() {
+			if err := visit(dependsModule); err != nil {
+				return err
+			}
+		}
+		visiting = visiting[:len(visiting)-1]
+
+		visited[moduleType] = true
 		modules = append(modules, farseerModule)
+		return nil
 	}
-	return modules
+
+	for _, farseerModule := range module {
+		if err := visit(farseerModule); err != nil {
+			return nil, err
+		}
+	}
+	return modules, nil
+}
+
+// 模块名称是否匹配，支持：tasks.Module、github.com/farseer-go/tasks.Module（不区分大小写）
+func isModuleName(moduleType reflect.Type, name string) bool {
+	name = strings.TrimSpace(name)
+	if strings.EqualFold(moduleType.String(), name) {
+		return true
+	}
+	if moduleType.Kind() == reflect.Pointer {
+		moduleType = moduleType.Elem()
+	}
+	return moduleType.PkgPath() != "" && strings.EqualFold(moduleType.PkgPath()+"."+moduleType.Name(), name)
 }
 
-// Distinct 模块去重
+// Distinct 模块去重（以模块的类型作为唯一标识），并将FarseerKernelModule放在第一位
 func Distinct(modules []FarseerModule) []FarseerModule {
-	var lst []FarseerModule
+	lst := []FarseerModule{FarseerKernelModule{}}
 	for _, module := range modules {
 		if !exists(lst, module) {
 			lst = append(lst, module)
 		}
 	}
-	return append([]FarseerModule{FarseerKernelModule{}}, lst...)
+	return lst
 }
 
 // 判断模块是否存在于数组中
 func exists(lst []FarseerModule, module FarseerModule) bool {
 	for _, farseerModule := range lst {
-		if farseerModule == module {
+		if reflect.TypeOf(farseerModule) == reflect.TypeOf(module) {
 			return true
 		}
 	}
 	return false
 }
 
-// StartModules 启动模块
-func StartModules(farseerModules []FarseerModule) {
-	flog.Println("Modules模块初始化...")
-	sw := stopwatch.StartNew()
-	for _, farseerModule := range farseerModules {
-		moduleName := reflect.TypeOf(farseerModule).String()
-		sw.Restart()
+// 模块的启动阶段
+var startPhases = []struct {
+	name string
+	run  func(ctx context.Context, farseerModule FarseerModule) error
+}{
+	{"PreInitialize", func(ctx context.Context, farseerModule FarseerModule) error {
+		if contextModule, ok := farseerModule.(FarseerModuleWithContext); ok {
+			return contextModule.PreInitializeContext(ctx)
+		}
 		farseerModule.PreInitialize()
-		flog.Println("耗时：" + sw.GetMillisecondsText() + moduleName + ".PreInitialize()")
-	}
-	flog.Println("---------------------------------------")
-
-	for _, farseerModule := range farseerModules {
-		moduleName := reflect.TypeOf(farseerModule).String()
-		sw.Restart()
+		return nil
+	}},
+	{"Initialize", func(ctx context.Context, farseerModule FarseerModule) error {
+		if contextModule, ok := farseerModule.(FarseerModuleWithContext); ok {
+			return contextModule.InitializeContext(ctx)
+		}
 		farseerModule.Initialize()
-		flog.Println("耗时：" + sw.GetMillisecondsText() + moduleName + ".Initialize()")
+		return nil
+	}},
+	{"PostInitialize", func(ctx context.Context, farseerModule FarseerModule) error {
+		if contextModule, ok := farseerModule.(FarseerModuleWithContext); ok {
+			return contextModule.PostInitializeContext(ctx)
+		}
+		farseerModule.PostInitialize()
+		return nil
+	}},
+}
+
+// StartModules 启动模块
+func StartModules(farseerModules []FarseerModule) error {
+	return StartModulesContext(context.Background(), farseerModules)
+}
+
+// StartModulesContext 启动模块，按PreInitialize、Initialize、PostInitialize分阶段执行
+// Modules.Parallel = true时，同一阶段中没有依赖关系的模块并行执行（FarseerKernelModule始终最先执行），全部模块完成后才进入下一阶段
+// 模块返回error（或ctx已取消）时中止启动，倒序关闭已完成PreInitialize的模块，并返回*StartupError
+// 模块发生panic时同样会先关闭已初始化的模块，再继续向上抛出
+func StartModulesContext(ctx context.Context, farseerModules []FarseerModule) error {
+	parallel := configure.GetBool("Modules.Parallel")
+	if parallel {
+		flog.Println("Modules模块初始化（并行）...")
+	} else {
+		flog.Println("Modules模块初始化...")
 	}
-	flog.Println("---------------------------------------")
 
-	for _, farseerModule := range farseerModules {
-		moduleName := reflect.TypeOf(farseerModule).String()
-		sw.Restart()
-		farseerModule.PostInitialize()
-		flog.Println("耗时：" + sw.GetMillisecondsText() + moduleName + ".PostInitialize()")
-		moduleMap[moduleName] = sw.ElapsedMilliseconds()
+	for phaseIndex := range startPhases {
+		var results []startResult
+		if parallel {
+			results = startPhaseParallel(ctx, farseerModules, phaseIndex)
+		} else {
+			results = startPhaseSerial(ctx, farseerModules, phaseIndex)
+		}
+
+		for index, result := range results {
+			if !result.isFail() {
+				continue
+			}
+			// 已初始化的模块：PreInitialize阶段为已执行成功的模块，之后的阶段为全部模块
+			initialized := farseerModules
+			if phaseIndex == 0 {
+				initialized = nil
+				for i, r := range results {
+					if r.isSuccess() {
+						initialized = append(initialized, farseerModules[i])
+					}
+				}
+			}
+			shutdownResults := ShutdownModules(initialized)
+			if result.exp != nil {
+				panic(result.exp)
+			}
+			return &StartupError{Module: reflect.TypeOf(farseerModules[index]).String(), Phase: startPhases[phaseIndex].name, Err: result.err, Shutdown: shutdownResults}
+		}
+		if phaseIndex < len(startPhases)-1 {
+			flog.Println("---------------------------------------")
+		}
 	}
 	flog.Println("基础组件初始化完成")
+	return nil
 }
 
-// ShutdownModules 关闭模块
-func ShutdownModules(farseerModules []FarseerModule) {
-	flog.Println("Modules模块关闭...")
+// 模块执行启动阶段的结果
+type startResult struct {
+	isRun bool  // 是否已执行
+	err   error // 返回的error
+	exp   any   // 发生的panic
+}
+
+// 是否执行成功
+func (r startResult) isSuccess() bool {
+	return r.isRun && r.err == nil && r.exp == nil
+}
+
+// 是否执行失败
+func (r startResult) isFail() bool {
+	return r.err != nil || r.exp != nil
+}
+
+// 按顺序执行模块的启动阶段，有模块失败时，后面的模块不再执行
+func startPhaseSerial(ctx context.Context, farseerModules []FarseerModule, phaseIndex int) []startResult {
+	results := make([]startResult, len(farseerModules))
+	for index, farseerModule := range farseerModules {
+		results[index] = startModule(ctx, farseerModule, phaseIndex)
+		if results[index].isFail() {
+			break
+		}
+	}
+	return results
+}
+
+// 并行执行模块的启动阶段，模块等待依赖的模块（及FarseerKernelModule）执行完后才开始执行
+// 有模块失败时，未开始的模块不再执行，已开始的模块执行完后返回
+func startPhaseParallel(ctx context.Context, farseerModules []FarseerModule, phaseIndex int) []startResult {
+	results := make([]startResult, len(farseerModules))
+	moduleIndex := make(map[reflect.Type]int)
+	kernelIndex := -1
+	for index, farseerModule := range farseerModules {
+		moduleType := reflect.TypeOf(farseerModule)
+		moduleIndex[moduleType] = index
+		if moduleType == reflect.TypeOf(FarseerKernelModule{}) {
+			kernelIndex = index
+		}
+	}
+
+	done := make([]chan struct{}, len(farseerModules))
+	for index := range farseerModules {
+		done[index] = make(chan struct{})
+	}
+
+	var failLock sync.Mutex
+	isFail := false
+	var wg sync.WaitGroup
+	for index, farseerModule := range farseerModules {
+		wg.Add(1)
+		go func(index int, farseerModule FarseerModule) {
+			defer wg.Done()
+			defer close(done[index])
+
+			// 等待依赖的模块（未加载的模块忽略）
+			if kernelIndex >= 0 && index != kernelIndex {
+				<-done[kernelIndex]
+			}
+			for _, dependsModule := range farseerModule.DependsModule() {
+				if dependIndex, exists := moduleIndex[reflect.TypeOf(dependsModule)]; exists && dependIndex != index {
+					<-done[dependIndex]
+				}
+			}
+
+			failLock.Lock()
+			skip := isFail
+			failLock.Unlock()
+			if skip {
+				return
+			}
+
+			results[index] = startModule(ctx, farseerModule, phaseIndex)
+			if results[index].isFail() {
+				failLock.Lock()
+				isFail = true
+				failLock.Unlock()
+			}
+		}(index, farseerModule)
+	}
+	wg.Wait()
+	return results
+}
+
+// 执行单个模块的启动阶段，并打印耗时
+func startModule(ctx context.Context, farseerModule FarseerModule, phaseIndex int) (result startResult) {
+	moduleName := reflect.TypeOf(farseerModule).String()
+	phase := startPhases[phaseIndex]
+	result.isRun = true
+	if phaseIndex == 0 {
+		setState(farseerModule, eumModuleState.Initializing, nil)
+	}
+	if result.err = ctx.Err(); result.err != nil {
+		flog.Errorf("%s.%s()启动失败：%s", moduleName, phase.name, result.err.Error())
+		setState(farseerModule, eumModuleState.Stopped, result.err)
+		return result
+	}
+
 	sw := stopwatch.StartNew()
-	for _, farseerModule := range farseerModules {
-		sw.Restart()
-		farseerModule.Shutdown()
-		flog.Println("耗时：" + sw.GetMillisecondsText() + reflect.TypeOf(farseerModule).String() + ".Shutdown()")
+	defer func() {
+		if result.exp = recover(); result.exp != nil {
+			flog.Errorf("%s.%s()启动失败：%v", moduleName, phase.name, result.exp)
+			setState(farseerModule, eumModuleState.Stopped, fmt.Errorf("%v", result.exp))
+		}
+	}()
+
+	if result.err = phase.run(ctx, farseerModule); result.err != nil {
+		flog.Errorf("%s.%s()启动失败：%s", moduleName, phase.name, result.err.Error())
+		setState(farseerModule, eumModuleState.Stopped, result.err)
+		return result
+	}
+	flog.Println("耗时：" + sw.GetMillisecondsText() + moduleName + "." + phase.name + "()")
+	setElapsed(farseerModule, phaseIndex, sw.ElapsedMilliseconds())
+	if phaseIndex == len(startPhases)-1 {
+		setLoad(farseerModule)
+	}
+	return result
+}
+
+// ShutdownModules 关闭模块，按启动顺序的倒序关闭（依赖方先关闭，被依赖的模块后关闭）
+// 单个模块超时：Modules.ShutdownTimeout（默认10s），全部模块超时：Modules.ShutdownTotalTimeout（默认30s）
+// 模块发生异常或超时后，会继续关闭下一个模块，并返回每个模块的关闭结果
+func ShutdownModules(farseerModules []FarseerModule) []ShutdownResult {
+	flog.Println("Modules模块关闭...")
+	moduleTimeout := configure.GetDuration("Modules.ShutdownTimeout")
+	if moduleTimeout <= 0 {
+		moduleTimeout = 10 * time.Second
+	}
+	totalTimeout := configure.GetDuration("Modules.ShutdownTotalTimeout")
+	if totalTimeout <= 0 {
+		totalTimeout = 30 * time.Second
+	}
+	deadline := time.Now().Add(totalTimeout)
+
+	var results []ShutdownResult
+	for i := len(farseerModules) - 1; i >= 0; i-- {
+		moduleName := reflect.TypeOf(farseerModules[i]).String()
+		// 已超过全部模块的超时时间，剩下的模块不再关闭
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			flog.Warningf("%s.Shutdown()未执行，已超过全部模块的关闭时间：%s", moduleName, totalTimeout.String())
+			results = append(results, ShutdownResult{Module: moduleName, IsTimeout: true})
+			setState(farseerModules[i], eumModuleState.Stopped, nil)
+			continue
+		}
+		if remaining > moduleTimeout {
+			remaining = moduleTimeout
+		}
+
+		result := shutdownModule(farseerModules[i], remaining)
+		setState(farseerModules[i], eumModuleState.Stopped, result.Err)
+		if result.IsTimeout {
+			flog.Warningf("%s.Shutdown()执行超时：%s", moduleName, remaining.String())
+		} else if result.Err != nil {
+			flog.Errorf("%s.Shutdown()执行失败：%s", moduleName, result.Err.Error())
+		} else {
+			flog.Println("耗时：" + flog.Colors[4](strconv.FormatInt(result.ElapsedMilliseconds, 10)+" ms ") + moduleName + ".Shutdown()")
+		}
+		results = append(results, result)
+	}
+
+	var failModules []string
+	for _, result := range results {
+		if result.IsTimeout || result.Err != nil {
+			failModules = append(failModules, result.Module)
+		}
+	}
+	if len(failModules) > 0 {
+		flog.Errorf("以下模块关闭失败或超时：%s", strings.Join(failModules, "、"))
 	}
 	flog.Println("---------------------------------------")
+	return results
+}
+
+// 关闭单个模块，超时后不再等待（Shutdown会继续在后台执行）
+func shutdownModule(farseerModule FarseerModule, timeout time.Duration) ShutdownResult {
+	result := ShutdownResult{Module: reflect.TypeOf(farseerModule).String()}
+	sw := stopwatch.StartNew()
+	done := make(chan error, 1)
+	go func() {
+		var err error
+		exception.Try(farseerModule.Shutdown).CatchException(func(exp any) {
+			err = fmt.Errorf("%v", exp)
+		})
+		done <- err
+	}()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case result.Err = <-done:
+	case <-timer.C:
+		result.IsTimeout = true
+	}
+	result.ElapsedMilliseconds = sw.ElapsedMilliseconds()
+	return result
 }
@@ -0,0 +1,204 @@
+package modules
+
+import (
+	"context"
+	"errors"
+	"github.com/farseer-go/fs/configure"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type testBaseModule struct{}
+
+func (testBaseModule) DependsModule() []FarseerModule { return nil }
+func (testBaseModule) PreInitialize()                 {}
+func (testBaseModule) Initialize()                    {}
+func (testBaseModule) PostInitialize()                {}
+func (testBaseModule) Shutdown()                      {}
+
+type testModuleA struct{ testBaseModule }
+type testModuleB struct{ testBaseModule }
+type testModuleC struct{ testBaseModule }
+type testStartupModule struct{ testBaseModule }
+
+func (testModuleA) DependsModule() []FarseerModule { return []FarseerModule{testModuleC{}} }
+func (testModuleB) DependsModule() []FarseerModule { return []FarseerModule{testModuleC{}} }
+func (testStartupModule) DependsModule() []FarseerModule {
+	return []FarseerModule{testModuleA{}, testModuleB{}}
+}
+
+type testCircularX struct{ testBaseModule }
+type testCircularY struct{ testBaseModule }
+
+func (testCircularX) DependsModule() []FarseerModule { return []FarseerModule{testCircularY{}} }
+func (testCircularY) DependsModule() []FarseerModule { return []FarseerModule{testCircularX{}} }
+
+func joinModuleNames(lst []FarseerModule) string {
+	var names []string
+	for _, module := range lst {
+		names = append(names, reflect.TypeOf(module).Name())
+	}
+	return strings.Join(names, ",")
+}
+
+func TestTopologicalSort(t *testing.T) {
+	lst, err := TopologicalSort(testStartupModule{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := joinModuleNames(lst); names != "testModuleC,testModuleA,testModuleB,testStartupModule" {
+		t.Fatalf("unexpected order: %s", names)
+	}
+	if names := joinModuleNames(Distinct(lst)); names != "FarseerKernelModule,testModuleC,testModuleA,testModuleB,testStartupModule" {
+		t.Fatalf("unexpected order after Distinct: %s", names)
+	}
+}
+
+func TestTopologicalSortCircular(t *testing.T) {
+	_, err := TopologicalSort(testCircularX{})
+	if err == nil || err.Error() != "modules：存在循环依赖：modules.testCircularX -> modules.testCircularY -> modules.testCircularX" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// 并行启动时，记录模块每个阶段的开始、结束
+type parallelModule struct {
+	testBaseModule
+	name    string
+	failAt  int             // 在第几个阶段返回error（-1时不失败）
+	waitFor <-chan struct{} // 失败前等待的信号
+	started chan struct{}   // PreInitialize开始时关闭
+}
+
+type parallelA struct{ parallelModule }
+type parallelB struct{ parallelModule }
+type parallelC struct{ parallelModule }
+type parallelD struct{ parallelModule }
+
+func (parallelA) DependsModule() []FarseerModule { return []FarseerModule{parallelC{}} }
+func (parallelB) DependsModule() []FarseerModule { return []FarseerModule{parallelC{}} }
+func (parallelD) DependsModule() []FarseerModule { return []FarseerModule{parallelA{}, parallelB{}} }
+
+var parallelLock sync.Mutex
+var parallelEvents []string
+
+func addParallelEvent(event string) {
+	parallelLock.Lock()
+	defer parallelLock.Unlock()
+	parallelEvents = append(parallelEvents, event)
+}
+
+// 事件的位置，不存在时返回-1
+func indexOfParallelEvent(event string) int {
+	for index, item := range parallelEvents {
+		if item == event {
+			return index
+		}
+	}
+	return -1
+}
+
+func (receiver parallelModule) run(phaseIndex int) error {
+	if phaseIndex == 0 && receiver.started != nil {
+		close(receiver.started)
+	}
+	addParallelEvent("start:" + strconv.Itoa(phaseIndex) + ":" + receiver.name)
+	defer addParallelEvent("end:" + strconv.Itoa(phaseIndex) + ":" + receiver.name)
+	if phaseIndex == receiver.failAt {
+		if receiver.waitFor != nil {
+			select {
+			case <-receiver.waitFor:
+			case <-time.After(2 * time.Second):
+			}
+		}
+		return errors.New("fail")
+	}
+	time.Sleep(10 * time.Millisecond)
+	return nil
+}
+
+func (receiver parallelModule) PreInitializeContext(context.Context) error  { return receiver.run(0) }
+func (receiver parallelModule) InitializeContext(context.Context) error     { return receiver.run(1) }
+func (receiver parallelModule) PostInitializeContext(context.Context) error { return receiver.run(2) }
+func (receiver parallelModule) Shutdown()                                   { addParallelEvent("shutdown:" + receiver.name) }
+
+func newParallelModules(failModule string, waitFor <-chan struct{}, started chan struct{}) []FarseerModule {
+	newModule := func(name string) parallelModule {
+		module := parallelModule{name: name, failAt: -1}
+		if name == failModule {
+			module.failAt = 0
+			module.waitFor = waitFor
+		}
+		if name == "B" {
+			module.started = started
+		}
+		return module
+	}
+	return []FarseerModule{
+		parallelC{newModule("C")},
+		parallelA{newModule("A")},
+		parallelB{newModule("B")},
+		parallelD{newModule("D")},
+	}
+}
+
+// 使用go test -race运行，并行启动时，每个阶段中模块都在依赖的模块执行完后才开始
+func TestStartModulesParallel(t *testing.T) {
+	t.Setenv("Modules_Parallel", "true")
+	_ = configure.ReadInConfig()
+	parallelEvents = nil
+
+	if err := StartModulesContext(context.Background(), newParallelModules("", nil, nil)); err != nil {
+		t.Fatal(err)
+	}
+	depends := map[string][]string{"A": {"C"}, "B": {"C"}, "D": {"A", "B"}}
+	for phaseIndex := range startPhases {
+		phase := strconv.Itoa(phaseIndex)
+		for _, name := range []string{"A", "B", "C", "D"} {
+			start := indexOfParallelEvent("start:" + phase + ":" + name)
+			if start < 0 {
+				t.Fatalf("module %s not started in phase %s", name, phase)
+			}
+			for _, dependName := range depends[name] {
+				if end := indexOfParallelEvent("end:" + phase + ":" + dependName); end < 0 || end > start {
+					t.Fatalf("module %s started before %s finished in phase %s: %v", name, dependName, phase, parallelEvents)
+				}
+			}
+		}
+	}
+	// 下一阶段在上一阶段全部完成后才开始
+	if indexOfParallelEvent("start:1:C") < indexOfParallelEvent("end:0:D") {
+		t.Fatalf("phase 1 started before phase 0 finished: %v", parallelEvents)
+	}
+}
+
+// 并行启动时，模块失败后未开始的模块不再执行，已初始化的模块被关闭
+func TestStartModulesParallelFail(t *testing.T) {
+	t.Setenv("Modules_Parallel", "true")
+	_ = configure.ReadInConfig()
+	parallelEvents = nil
+
+	// A等到B开始后才失败，D依赖A、B，不会开始
+	started := make(chan struct{})
+	err := StartModulesContext(context.Background(), newParallelModules("A", started, started))
+	var startupErr *StartupError
+	if !errors.As(err, &startupErr) || startupErr.Module != "modules.parallelA" || startupErr.Phase != "PreInitialize" {
+		t.Fatalf("expected *StartupError of parallelA.PreInitialize, got: %v", err)
+	}
+	if indexOfParallelEvent("start:0:D") >= 0 {
+		t.Fatalf("module D should not start: %v", parallelEvents)
+	}
+	var shutdown []string
+	for _, event := range parallelEvents {
+		if strings.HasPrefix(event, "shutdown:") {
+			shutdown = append(shutdown, strings.TrimPrefix(event, "shutdown:"))
+		}
+	}
+	if strings.Join(shutdown, ",") != "B,C" {
+		t.Fatalf("expected B,C to be shut down, got: %v", shutdown)
+	}
+}
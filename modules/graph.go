@@ -0,0 +1,62 @@
+package modules
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ModuleGraph 模块的依赖关系
+type ModuleGraph struct {
+	Module  string   `json:"module"`  // 模块名称
+	Depends []string `json:"depends"` // 依赖的模块
+}
+
+// GetGraph 获取模块的依赖关系（依赖的模块排在前面）
+func GetGraph(module ...FarseerModule) []ModuleGraph {
+	lst := []ModuleGraph{}
+	visited := make(map[string]bool)
+	var walk func(farseerModules []FarseerModule)
+	walk = func(farseerModules []FarseerModule) {
+		for _, farseerModule := range farseerModules {
+			moduleName := reflect.TypeOf(farseerModule).String()
+			if visited[moduleName] {
+				continue
+			}
+			visited[moduleName] = true
+
+			dependsModules := farseerModule.DependsModule()
+			walk(dependsModules)
+
+			graph := ModuleGraph{Module: moduleName, Depends: []string{}}
+			for _, dependsModule := range dependsModules {
+				graph.Depends = append(graph.Depends, reflect.TypeOf(dependsModule).String())
+			}
+			lst = append(lst, graph)
+		}
+	}
+	walk(module)
+	return lst
+}
+
+// GraphToJson 以JSON格式输出模块的依赖关系
+func GraphToJson(module ...FarseerModule) string {
+	data, _ := json.MarshalIndent(GetGraph(module...), "", "  ")
+	return string(data)
+}
+
+// GraphToDot 以Graphviz DOT格式输出模块的依赖关系
+func GraphToDot(module ...FarseerModule) string {
+	var sb strings.Builder
+	sb.WriteString("digraph modules {\n")
+	sb.WriteString("  node [shape=box];\n")
+	for _, graph := range GetGraph(module...) {
+		sb.WriteString(fmt.Sprintf("  %q;\n", graph.Module))
+		for _, depend := range graph.Depends {
+			sb.WriteString(fmt.Sprintf("  %q -> %q;\n", graph.Module, depend))
+		}
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
@@ -0,0 +1,144 @@
+package modules
+
+import (
+	"context"
+	"fmt"
+	"github.com/farseer-go/fs/configure"
+	"github.com/farseer-go/fs/exception"
+	"github.com/farseer-go/fs/modules/eumModuleState"
+	"github.com/farseer-go/fs/stopwatch"
+	"sync"
+	"time"
+)
+
+// HealthResult 模块的健康状态
+type HealthResult struct {
+	Module              string    `json:"module"`              // 模块名称
+	State               string    `json:"state"`               // 状态：Initializing、Ready、Degraded、Stopped
+	LatencyMilliseconds int64     `json:"latencyMilliseconds"` // 最近一次健康检查的耗时（毫秒）
+	LastError           string    `json:"lastError"`           // 最近一次启动、健康检查、关闭时的异常
+	CheckAt             time.Time `json:"checkAt"`             // 最近一次健康检查的时间
+}
+
+// HealthReport 汇总的健康状态
+type HealthReport struct {
+	IsHealthy bool           `json:"isHealthy"` // 是否健康
+	Modules   []HealthResult `json:"modules"`   // 每个模块的健康状态（按启动的顺序）
+}
+
+// Liveness 存活检查（不执行模块的健康检查），没有模块处于Stopped状态时为健康
+func Liveness() HealthReport {
+	report := getHealthReport()
+	for _, result := range report.Modules {
+		if result.State == eumModuleState.Stopped.ToString() {
+			report.IsHealthy = false
+		}
+	}
+	return report
+}
+
+// Readiness 就绪检查，并行执行已加载模块的HealthCheck（单个模块超时：Modules.HealthCheckTimeout，默认3s）
+// 全部模块处于Ready状态时为健康
+func Readiness(ctx context.Context) HealthReport {
+	timeout := configure.GetDuration("Modules.HealthCheckTimeout")
+	if timeout <= 0 {
+		timeout = 3 * time.Second
+	}
+
+	var wg sync.WaitGroup
+	for _, status := range getLoadStatus() {
+		if _, ok := status.module.(FarseerModuleWithHealthCheck); !ok {
+			continue
+		}
+		wg.Add(1)
+		go func(status *moduleStatus) {
+			defer wg.Done()
+			healthCheck(ctx, status, timeout)
+		}(status)
+	}
+	wg.Wait()
+
+	report := getHealthReport()
+	for _, result := range report.Modules {
+		if result.State != eumModuleState.Ready.ToString() {
+			report.IsHealthy = false
+		}
+	}
+	return report
+}
+
+// 执行模块的健康检查，超时后不再等待，并更新模块的状态
+func healthCheck(ctx context.Context, status *moduleStatus, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	sw := stopwatch.StartNew()
+	done := make(chan error, 1)
+	go func() {
+		var err error
+		exception.Try(func() {
+			err = status.module.(FarseerModuleWithHealthCheck).HealthCheck(ctx)
+		}).CatchException(func(exp any) {
+			err = fmt.Errorf("%v", exp)
+		})
+		done <- err
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = fmt.Errorf("健康检查超时：%s", timeout.String())
+	}
+
+	moduleLock.Lock()
+	defer moduleLock.Unlock()
+	status.latency = sw.ElapsedMilliseconds()
+	status.checkAt = time.Now()
+	if err != nil {
+		status.lastError = err
+	}
+	// 启动中、已停止的模块不改变状态
+	if status.state == eumModuleState.Ready || status.state == eumModuleState.Degraded {
+		if err != nil {
+			status.state = eumModuleState.Degraded
+		} else {
+			status.state = eumModuleState.Ready
+		}
+	}
+}
+
+// 获取已加载（未停止）的模块
+func getLoadStatus() []*moduleStatus {
+	moduleLock.RLock()
+	defer moduleLock.RUnlock()
+	var lst []*moduleStatus
+	for _, moduleName := range moduleNames {
+		status := moduleMap[moduleName]
+		if status.state == eumModuleState.Ready || status.state == eumModuleState.Degraded {
+			lst = append(lst, status)
+		}
+	}
+	return lst
+}
+
+// 汇总模块的状态，没有模块时为不健康
+func getHealthReport() HealthReport {
+	moduleLock.RLock()
+	defer moduleLock.RUnlock()
+	report := HealthReport{IsHealthy: len(moduleNames) > 0}
+	for _, moduleName := range moduleNames {
+		status := moduleMap[moduleName]
+		result := HealthResult{
+			Module:              moduleName,
+			State:               status.state.ToString(),
+			LatencyMilliseconds: status.latency,
+			CheckAt:             status.checkAt,
+		}
+		if status.lastError != nil {
+			result.LastError = status.lastError.Error()
+		}
+		report.Modules = append(report.Modules, result)
+	}
+	return report
+}
@@ -2,17 +2,83 @@ package modules
 
 import (
 	"github.com/farseer-go/fs/flog"
+	"github.com/farseer-go/fs/modules/eumModuleState"
 	"os"
 	"reflect"
+	"sync"
+	"time"
 )
 
-var moduleMap = make(map[string]int64)
+// 模块的状态
+type moduleStatus struct {
+	module              FarseerModule
+	state               eumModuleState.Enum
+	isLoad              bool      // 是否已完成PostInitialize
+	elapsedMilliseconds [3]int64  // 启动各阶段的耗时（PreInitialize、Initialize、PostInitialize）
+	latency             int64     // 最近一次健康检查的耗时（毫秒）
+	lastError           error     // 最近一次启动、健康检查、关闭时的异常
+	checkAt             time.Time // 最近一次健康检查的时间
+}
+
+var moduleMap = make(map[string]*moduleStatus)
+var moduleNames []string // 按启动的顺序
+var moduleLock sync.RWMutex
+
+// 获取模块的状态，不存在时创建，调用方需持有锁
+func getStatus(module FarseerModule) *moduleStatus {
+	moduleName := reflect.TypeOf(module).String()
+	status, exists := moduleMap[moduleName]
+	if !exists {
+		status = &moduleStatus{module: module}
+		moduleMap[moduleName] = status
+		moduleNames = append(moduleNames, moduleName)
+	}
+	return status
+}
+
+// 设置模块的状态，err不为nil时记录为最近一次的异常
+func setState(module FarseerModule, state eumModuleState.Enum, err error) {
+	moduleLock.Lock()
+	defer moduleLock.Unlock()
+	status := getStatus(module)
+	status.state = state
+	if err != nil {
+		status.lastError = err
+	}
+}
+
+// 记录模块启动阶段的耗时
+func setElapsed(module FarseerModule, phaseIndex int, elapsedMilliseconds int64) {
+	moduleLock.Lock()
+	defer moduleLock.Unlock()
+	getStatus(module).elapsedMilliseconds[phaseIndex] = elapsedMilliseconds
+}
+
+// 记录已加载的模块
+func setLoad(module FarseerModule) {
+	moduleLock.Lock()
+	defer moduleLock.Unlock()
+	status := getStatus(module)
+	status.state = eumModuleState.Ready
+	status.isLoad = true
+}
 
 // IsLoad 模块是否加载
 func IsLoad(module FarseerModule) bool {
-	moduleName := reflect.TypeOf(module).String()
-	_, isExists := moduleMap[moduleName]
-	return isExists
+	moduleLock.RLock()
+	defer moduleLock.RUnlock()
+	status, isExists := moduleMap[reflect.TypeOf(module).String()]
+	return isExists && status.isLoad
+}
+
+// GetState 获取模块的状态
+func GetState(module FarseerModule) eumModuleState.Enum {
+	moduleLock.RLock()
+	defer moduleLock.RUnlock()
+	if status, isExists := moduleMap[reflect.TypeOf(module).String()]; isExists {
+		return status.state
+	}
+	return eumModuleState.Unloaded
 }
 
 // ThrowIfNotLoad 如果没加载模块时，退出应用
@@ -0,0 +1,28 @@
+package modules
+
+// ModuleReport 模块的启动耗时
+type ModuleReport struct {
+	Module                     string `json:"module"`                     // 模块名称
+	State                      string `json:"state"`                      // 状态：Initializing、Ready、Degraded、Stopped
+	PreInitializeMilliseconds  int64  `json:"preInitializeMilliseconds"`  // PreInitialize的耗时（毫秒）
+	InitializeMilliseconds     int64  `json:"initializeMilliseconds"`     // Initialize的耗时（毫秒）
+	PostInitializeMilliseconds int64  `json:"postInitializeMilliseconds"` // PostInitialize的耗时（毫秒）
+}
+
+// GetModuleReport 获取模块的启动耗时（按启动的顺序）
+func GetModuleReport() []ModuleReport {
+	moduleLock.RLock()
+	defer moduleLock.RUnlock()
+	var lst []ModuleReport
+	for _, moduleName := range moduleNames {
+		status := moduleMap[moduleName]
+		lst = append(lst, ModuleReport{
+			Module:                     moduleName,
+			State:                      status.state.ToString(),
+			PreInitializeMilliseconds:  status.elapsedMilliseconds[0],
+			InitializeMilliseconds:     status.elapsedMilliseconds[1],
+			PostInitializeMilliseconds: status.elapsedMilliseconds[2],
+		})
+	}
+	return lst
+}
@@ -0,0 +1,9 @@
+package modules
+
+// ShutdownResult 模块关闭的结果
+type ShutdownResult struct {
+	Module              string // 模块名称
+	ElapsedMilliseconds int64  // 耗时（毫秒）
+	Err                 error  // Shutdown发生的异常
+	IsTimeout           bool   // 是否超时（或因超过全部模块的关闭时间而未执行）
+}
@@ -0,0 +1,33 @@
+package modules
+
+import (
+	"strings"
+)
+
+// StartupError 模块启动失败
+type StartupError struct {
+	Module   string           // 启动失败的模块
+	Phase    string           // 启动失败的阶段：PreInitialize、Initialize、PostInitialize
+	Err      error            // 失败原因
+	Shutdown []ShutdownResult // 已初始化模块的关闭结果
+}
+
+func (e *StartupError) Error() string {
+	msg := "modules：" + e.Module + "." + e.Phase + "()启动失败：" + e.Err.Error()
+	var failModules []string
+	for _, result := range e.Shutdown {
+		if result.IsTimeout {
+			failModules = append(failModules, result.Module+"（超时）")
+		} else if result.Err != nil {
+			failModules = append(failModules, result.Module+"（"+result.Err.Error()+"）")
+		}
+	}
+	if len(failModules) > 0 {
+		msg += "；以下模块关闭失败：" + strings.Join(failModules, "、")
+	}
+	return msg
+}
+
+func (e *StartupError) Unwrap() error {
+	return e.Err
+}
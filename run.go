@@ -0,0 +1,103 @@
+package fs
+
+import (
+	"context"
+	"github.com/farseer-go/fs/configure"
+	"github.com/farseer-go/fs/exception"
+	"github.com/farseer-go/fs/flog"
+	"github.com/farseer-go/fs/stopwatch"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// 应用的根上下文，应用退出时取消，每次Initialize时重新创建
+var rootCtx, rootCancel = context.WithCancel(context.Background())
+var rootLock sync.RWMutex
+
+// 后台任务
+var workerWait sync.WaitGroup
+
+// Context 应用的根上下文，收到退出信号（或调用Exit）时取消，后台任务可以通过它感知应用退出
+func Context() context.Context {
+	rootLock.RLock()
+	defer rootLock.RUnlock()
+	return rootCtx
+}
+
+// 创建新的根上下文（上一次的根上下文会被取消）
+func newRootContext() context.Context {
+	rootLock.Lock()
+	defer rootLock.Unlock()
+	rootCancel()
+	rootCtx, rootCancel = context.WithCancel(context.Background())
+	return rootCtx
+}
+
+// 取消根上下文
+func cancelRootContext() {
+	rootLock.RLock()
+	defer rootLock.RUnlock()
+	rootCancel()
+}
+
+// AddWorker 启动后台任务，Run退出时会取消ctx，并等待后台任务结束
+func AddWorker(name string, fn func(ctx context.Context)) {
+	ctx := Context()
+	workerWait.Add(1)
+	go func() {
+		defer workerWait.Done()
+		exception.Try(func() {
+			fn(ctx)
+		}).CatchException(func(exp any) {
+			flog.Errorf("后台任务%s发生异常：%v", name, exp)
+		})
+	}()
+}
+
+// Run 阻塞直到收到SIGINT/SIGTERM，然后取消根上下文、等待后台任务结束（最长Run.DrainTimeout，默认30s）、关闭模块
+// 退出过程中再次收到信号时，立即强制退出
+func Run() {
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(signals)
+
+	sig := <-signals
+	flog.Println("收到退出信号：", sig.String(), "，应用开始退出...")
+	cancelRootContext()
+
+	// 再次收到信号，强制退出
+	go func() {
+		sig := <-signals
+		flog.Warningf("再次收到退出信号：%s，强制退出", sig.String())
+		os.Exit(1)
+	}()
+
+	drainWorkers()
+	Exit()
+}
+
+// 等待后台任务结束
+func drainWorkers() {
+	timeout := configure.GetDuration("Run.DrainTimeout")
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+
+	sw := stopwatch.StartNew()
+	done := make(chan struct{})
+	go func() {
+		workerWait.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		flog.Println("后台任务已全部结束，耗时：" + sw.GetMillisecondsText())
+	case <-time.After(timeout):
+		flog.Warningf("等待后台任务结束超时：%s", timeout.String())
+	}
+	flog.Println("---------------------------------------")
+}
@@ -0,0 +1,98 @@
+package fs
+
+import (
+	"encoding/json"
+	"github.com/farseer-go/fs/configure"
+	"github.com/farseer-go/fs/container"
+	"github.com/farseer-go/fs/flog"
+	"github.com/farseer-go/fs/modules"
+	"os"
+	"sync"
+	"time"
+)
+
+// StartupReport 应用的启动报告
+type StartupReport struct {
+	AppName             string                     `json:"appName"`             // 应用名称
+	Environment         string                     `json:"environment"`         // 运行环境
+	HostName            string                     `json:"hostName"`            // 主机名称
+	AppId               int64                      `json:"appId"`               // 应用ID
+	AppIp               string                     `json:"appIp"`               // 应用IP
+	ProcessId           int                        `json:"processId"`           // 进程Id
+	StartupAt           time.Time                  `json:"startupAt"`           // 应用启动时间
+	ConfigSources       []string                   `json:"configSources"`       // 配置来源（按优先级）
+	Modules             []modules.ModuleReport     `json:"modules"`             // 模块的启动耗时
+	Components          []container.ComponentGraph `json:"components"`          // 容器中注册的组件
+	Callbacks           []CallbackReport           `json:"callbacks"`           // 启动完后执行的函数
+	ElapsedMilliseconds int64                      `json:"elapsedMilliseconds"` // 启动的总耗时（毫秒）
+	Error               string                     `json:"error"`               // 启动失败的原因
+}
+
+// CallbackReport 启动完后执行的函数的耗时
+type CallbackReport struct {
+	Name                string `json:"name"`                // 函数名称
+	Order               int    `json:"order"`               // 执行顺序
+	Async               bool   `json:"async"`               // 是否异步执行
+	ElapsedMilliseconds int64  `json:"elapsedMilliseconds"` // 耗时（毫秒），异步执行的函数执行完后才有值
+	Error               string `json:"error"`               // 执行失败的原因
+}
+
+var startupReport StartupReport
+var callbackReports []CallbackReport // 异步执行的函数会在启动完后更新
+var reportLock sync.RWMutex
+
+// GetStartupReport 获取应用的启动报告（fs.Initialize执行完后可用）
+func GetStartupReport() StartupReport {
+	reportLock.RLock()
+	defer reportLock.RUnlock()
+	return getStartupReport()
+}
+
+// 获取启动报告，调用方需持有锁
+func getStartupReport() StartupReport {
+	report := startupReport
+	report.Callbacks = make([]CallbackReport, len(callbackReports))
+	copy(report.Callbacks, callbackReports)
+	return report
+}
+
+// 记录启动完后执行的函数的耗时
+func setCallbackReport(index int, elapsedMilliseconds int64, err error) {
+	reportLock.Lock()
+	defer reportLock.Unlock()
+	callbackReports[index].ElapsedMilliseconds = elapsedMilliseconds
+	if err != nil {
+		callbackReports[index].Error = err.Error()
+	}
+}
+
+// 生成启动报告，配置了Startup.ReportPath时，以JSON格式写入到文件
+func buildStartupReport(elapsedMilliseconds int64, err error) {
+	reportLock.Lock()
+	defer reportLock.Unlock()
+	startupReport = StartupReport{
+		AppName:             AppName,
+		Environment:         Environment,
+		HostName:            HostName,
+		AppId:               AppId,
+		AppIp:               AppIp,
+		ProcessId:           ProcessId,
+		StartupAt:           StartupAt.ToTime(),
+		ConfigSources:       configure.GetSources(),
+		Modules:             modules.GetModuleReport(),
+		Components:          container.GetGraph(),
+		ElapsedMilliseconds: elapsedMilliseconds,
+	}
+	if err != nil {
+		startupReport.Error = err.Error()
+	}
+
+	reportPath := configure.GetString("Startup.ReportPath")
+	if reportPath == "" {
+		return
+	}
+	data, _ := json.MarshalIndent(getStartupReport(), "", "  ")
+	if writeErr := os.WriteFile(reportPath, data, 0644); writeErr != nil {
+		flog.Errorf("启动报告写入%s失败：%s", reportPath, writeErr.Error())
+	}
+}